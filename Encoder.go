@@ -0,0 +1,11 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+// Encoder An Encoder serializes log entries for appenders shipping them to other processes
+type Encoder interface {
+	// Encode appends the encoded entry, terminated by a new line, to the buffer and returns the extended buffer
+	Encode(buf []byte, logEntry *LogEntry) []byte
+}
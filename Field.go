@@ -0,0 +1,101 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gomatbase/go-error"
+)
+
+// ErrorKey is the key of the field created by Err
+const ErrorKey = "error"
+
+// Field is a key/value pair attached to a log entry. Fields passed as logged values are not part of the message but
+// are added to the entry's fields instead.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// ErrorInfo describes an error logged in an entry, including the chain of errors it wraps
+type ErrorInfo struct {
+	Message string      `json:"message"`          // the error message
+	Type    string      `json:"type"`             // the go type of the error
+	Kind    string      `json:"kind,omitempty"`   // the go-error err.Error constant the error is or wraps, if any
+	Causes  []ErrorInfo `json:"causes,omitempty"` // the errors wrapped by the error (through errors.Unwrap or errors.Join)
+}
+
+// F creates a field with the given key and value
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err creates a field holding the given error. The error will also be recorded in the entry's errors.
+func Err(e error) Field {
+	return Field{Key: ErrorKey, Value: e}
+}
+
+// newErrorInfo builds the error information of an error and of all the errors it wraps. Errors which are nil pointers
+// are recorded as fmt formats them, without calling their methods.
+func newErrorInfo(e error) ErrorInfo {
+	if value := reflect.ValueOf(e); value.Kind() == reflect.Pointer && value.IsNil() {
+		return ErrorInfo{Message: fmt.Sprint(e), Type: fmt.Sprintf("%T", e)}
+	}
+	info := ErrorInfo{
+		Message: e.Error(),
+		Type:    fmt.Sprintf("%T", e),
+	}
+	var kind err.Error
+	if errors.As(e, &kind) {
+		info.Kind = string(kind)
+	}
+	switch wrapper := e.(type) {
+	case interface{ Unwrap() []error }:
+		for _, cause := range wrapper.Unwrap() {
+			if cause != nil {
+				info.Causes = append(info.Causes, newErrorInfo(cause))
+			}
+		}
+	case interface{ Unwrap() error }:
+		if cause := wrapper.Unwrap(); cause != nil {
+			info.Causes = []ErrorInfo{newErrorInfo(cause)}
+		}
+	}
+	return info
+}
+
+// messageValues returns the logged values which are part of the message, leaving out fields. The original slice is
+// returned if it holds no fields.
+func messageValues(v []interface{}) []interface{} {
+	for i, value := range v {
+		if _, isField := value.(Field); isField {
+			values := append(make([]interface{}, 0, len(v)), v[:i]...)
+			for _, value := range v[i+1:] {
+				if _, isField := value.(Field); !isField {
+					values = append(values, value)
+				}
+			}
+			return values
+		}
+	}
+	return v
+}
+
+// recordValues adds the fields found in the logged values to the log entry, as well as the information of any logged
+// error, either directly or as a field value
+func (logEntry *LogEntry) recordValues(v []interface{}) {
+	for _, value := range v {
+		if field, isField := value.(Field); isField {
+			logEntry.Fields = append(logEntry.Fields, field)
+			value = field.Value
+		}
+		if e, isError := value.(error); isError && e != nil {
+			logEntry.Errors = append(logEntry.Errors, newErrorInfo(e))
+		}
+	}
+}
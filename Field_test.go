@@ -0,0 +1,87 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomatbase/go-error"
+)
+
+const errTestKind = err.Error("test kind")
+
+func TestErrorValues(t *testing.T) {
+	t.Run("Test error arguments are recorded", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("ERRORS", SyncedAppenders().WithAppenders(appender).(*options))
+		logger.Error("failed:", fmt.Errorf("wrapped: %w", errTestKind))
+
+		entry := appender.entries[0]
+		if entry.Message != "failed: wrapped: test kind\n" {
+			t.Errorf("Unexpected message: %q", entry.Message)
+		}
+		if len(entry.Errors) != 1 {
+			t.Fatal("Unexpected number of recorded errors :", len(entry.Errors))
+		}
+		info := entry.Errors[0]
+		if info.Message != "wrapped: test kind" || info.Type != "*fmt.wrapError" || info.Kind != "test kind" {
+			t.Error("Unexpected error information :", info)
+		}
+		if len(info.Causes) != 1 || info.Causes[0].Type != "err.Error" || info.Causes[0].Kind != "test kind" {
+			t.Error("Unexpected error chain :", info.Causes)
+		}
+	})
+
+	t.Run("Test joined errors are recorded", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("ERRORS", SyncedAppenders().WithAppenders(appender).(*options))
+		logger.Errorf("failed %d times", 2, Err(errors.Join(errors.New("first"), errTestKind)))
+
+		entry := appender.entries[0]
+		if entry.Message != "failed 2 times" {
+			t.Errorf("Unexpected message: %q", entry.Message)
+		}
+		if len(entry.Fields) != 1 || entry.Fields[0].Key != ErrorKey {
+			t.Error("Error field not recorded :", entry.Fields)
+		}
+		if len(entry.Errors) != 1 {
+			t.Fatal("Unexpected number of recorded errors :", len(entry.Errors))
+		}
+		if causes := entry.Errors[0].Causes; len(causes) != 2 || causes[0].Message != "first" || causes[0].Kind != "" || causes[1].Kind != "test kind" {
+			t.Error("Unexpected joined error chain :", causes)
+		}
+	})
+
+	t.Run("Test fields are written by standard writer", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger, _ := newLogger("ERRORS", Standard().WithWriter(buffer).(*options))
+		logger.Error("failed", Err(errTestKind), F("attempt", 3))
+
+		if buffer.String() != "failed error=\"test kind\" attempt=3\n" {
+			t.Errorf("Unexpected output: %q", buffer.String())
+		}
+	})
+	t.Run("Test nil pointer errors are recorded", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger, _ := newLogger("ERRORS", Standard().WithWriter(buffer).(*options))
+		logger.Error("failed", Err((*pointerError)(nil)))
+
+		if buffer.String() != "failed error=<nil>\n" {
+			t.Errorf("Unexpected output: %q", buffer.String())
+		}
+	})
+}
+
+// pointerError error dereferencing its receiver
+type pointerError struct {
+	message string
+}
+
+func (pe *pointerError) Error() string {
+	return pe.message
+}
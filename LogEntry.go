@@ -5,9 +5,16 @@ import (
 )
 
 type LogEntry struct {
-	Timestamp time.Time
-	Level     int
-	Message   string
-	Source    *string
-	Line      int
+	Logger       string // name of the logger the entry was logged with
+	Timestamp    time.Time
+	Level        int
+	Message      string
+	Source       *string
+	Line         int
+	Function     string       // name of the calling function, qualified with its receiver type for methods
+	Package      string       // import path of the package of the calling function
+	ModuleSource string       // source file path relative to the root of its module
+	Stack        []StackFrame // stack trace captured for the entry, if the logger captures them for its level
+	Fields       []Field      // fields logged with the message
+	Errors       []ErrorInfo  // information of the errors logged with the message, either as values or fields
 }
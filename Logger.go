@@ -1,5 +1,37 @@
 package log
 
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// managedLogger is implemented by the package's loggers, allowing registries to manage them
+type managedLogger interface {
+	// loggerOptions returns the options the logger was created with
+	loggerOptions() *options
+
+	// setCallDepth sets the number of frames to skip to get to the caller of the logging function
+	setCallDepth(int)
+
+	// flush outputs the entries held back by sampling and deduplication
+	flush()
+
+	// log outputs an entry built outside the logger, with its caller already resolved. The level of the entry is not
+	// checked if the entry is already known to be enabled.
+	log(entry *LogEntry, enabled bool)
+
+	// verbose returns true if the given verbosity level is enabled for the caller at the given call depth, relative
+	// to the function calling verbose
+	verbose(level int, callDepth int) bool
+
+	// vmodules returns the current verbosity of the source files matching patterns
+	vmodules() []VModule
+
+	// reconfigure applies the options, of the same logger type, to the logger in place
+	reconfigure(*options)
+}
+
 // Logger defines the interface a Logger implementation must provide
 type Logger interface {
 
@@ -9,6 +41,58 @@ type Logger interface {
 	// Level returns the current log level of the logger
 	Level() int
 
+	// Enabled returns true if entries at the given level are logged by the logger
+	Enabled(level int) bool
+
+	// EnabledCtx returns true if entries at the given level are logged by the logger when logged with the context,
+	// either because of the logger's level or because the context enables the level (see WithEnabledLevel)
+	EnabledCtx(ctx context.Context, level int) bool
+
+	// V returns the handle logging entries of the given verbosity level, enabled if the verbosity of the logger, or of
+	// the vmodule matching the caller's source file, is at least that level
+	V(level int) Verbose
+
+	// SetVerbosity sets the verbosity of the logger
+	SetVerbosity(verbosity int)
+
+	// Verbosity returns the verbosity of the logger
+	Verbosity() int
+
+	// SetVModule sets the verbosity of the source files matching patterns, as a comma separated list of
+	// pattern=verbosity pairs (like "db*=2,controllers/*=4")
+	SetVModule(spec string) error
+
+	// PushLevel temporarily sets the level of the logger until the returned function is called, which restores the
+	// previous level. Overrides may be nested and restored in any order, setting the level explicitly discards them.
+	PushLevel(level int) (restore func())
+
+	// OverrideLevel temporarily sets the level of the logger for the given duration, or until the returned function
+	// is called, after which the previous level is restored as with PushLevel
+	OverrideLevel(level int, duration time.Duration) (restore func())
+
+	// Writer returns a writer logging each line written to it as an entry at the given level, buffering partial lines
+	// (see NewLineWriter)
+	Writer(level int) io.WriteCloser
+
+	// SetWriter swaps the writer of a standard logger. Returns ErrUnsupportedByLoggerType for other loggers.
+	SetWriter(writer io.Writer) error
+
+	// SetAppenders swaps the appenders of a synced appenders logger. Returns ErrUnsupportedByLoggerType for other
+	// loggers.
+	SetAppenders(appenders ...Appender) error
+
+	// SetLevelLogPrefix sets the log prefix format for a specific level
+	SetLevelLogPrefix(level int, flags ...uint)
+
+	// SetLogPrefix sets the log prefix format for all levels
+	SetLogPrefix(flags ...uint)
+
+	// SetDateFlags sets the format flags of the date in the log prefix
+	SetDateFlags(flags int)
+
+	// SetFailingCriticals sets if logging a critical should result in a fatal entry (panic)
+	SetFailingCriticals(failing bool)
+
 	// Critical logs the message(s) at the critical level
 	Critical(v ...interface{})
 
@@ -44,4 +128,40 @@ type Logger interface {
 
 	// Tracef logs the formatted message at the trace level
 	Tracef(format string, v ...interface{})
+
+	// CriticalCtx logs the message(s) at the critical level with the fields extracted from the context
+	CriticalCtx(ctx context.Context, v ...interface{})
+
+	// CriticalfCtx logs the formatted message at the critical level with the fields extracted from the context
+	CriticalfCtx(ctx context.Context, format string, v ...interface{})
+
+	// ErrorCtx logs the message(s) at the error level with the fields extracted from the context
+	ErrorCtx(ctx context.Context, v ...interface{})
+
+	// ErrorfCtx logs the formatted message at the error level with the fields extracted from the context
+	ErrorfCtx(ctx context.Context, format string, v ...interface{})
+
+	// WarningCtx logs the message(s) at the warning level with the fields extracted from the context
+	WarningCtx(ctx context.Context, v ...interface{})
+
+	// WarningfCtx logs the formatted message at the warning level with the fields extracted from the context
+	WarningfCtx(ctx context.Context, format string, v ...interface{})
+
+	// InfoCtx logs the message(s) at the info level with the fields extracted from the context
+	InfoCtx(ctx context.Context, v ...interface{})
+
+	// InfofCtx logs the formatted message at the info level with the fields extracted from the context
+	InfofCtx(ctx context.Context, format string, v ...interface{})
+
+	// DebugCtx logs the message(s) at the debug level with the fields extracted from the context
+	DebugCtx(ctx context.Context, v ...interface{})
+
+	// DebugfCtx logs the formatted message at the debug level with the fields extracted from the context
+	DebugfCtx(ctx context.Context, format string, v ...interface{})
+
+	// TraceCtx logs the message(s) at the trace level with the fields extracted from the context
+	TraceCtx(ctx context.Context, v ...interface{})
+
+	// TracefCtx logs the formatted message at the trace level with the fields extracted from the context
+	TracefCtx(ctx context.Context, format string, v ...interface{})
 }
@@ -0,0 +1,130 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// callerInfo holds the source information resolved from a program counter
+type callerInfo struct {
+	function     string // name of the function, qualified with its receiver type for methods
+	pkg          string // import path of the package the function belongs to
+	moduleSource string // source file path relative to the root of the module holding the package
+}
+
+var (
+	callers       sync.Map  // cache of resolved caller information indexed by program counter
+	modules       []string  // paths of the modules known to the running binary
+	modulesLoader sync.Once // guard to load the module paths only once
+)
+
+// loadModules reads the paths of the main module and its dependencies from the binary build information
+func loadModules() {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if len(info.Main.Path) > 0 {
+			modules = append(modules, info.Main.Path)
+		}
+		for _, dependency := range info.Deps {
+			modules = append(modules, dependency.Path)
+		}
+	}
+}
+
+// splitFunctionName splits a fully qualified function name as reported by the runtime into its package path and the
+// function name (e.g. "github.com/a/b.(*T).m" becomes "github.com/a/b" and "(*T).m")
+func splitFunctionName(name string) (string, string) {
+	slash := strings.LastIndexByte(name, '/')
+	if dot := strings.IndexByte(name[slash+1:], '.'); dot >= 0 {
+		return name[:slash+1+dot], name[slash+2+dot:]
+	}
+	return "", name
+}
+
+// moduleRelativeSource builds the module relative path of the file holding the source of the given package. If the
+// package is not found in any known module, only the file name is returned.
+func moduleRelativeSource(pkg string, file string) string {
+	modulesLoader.Do(loadModules)
+	module := ""
+	for _, m := range modules {
+		if len(m) > len(module) && (pkg == m || strings.HasPrefix(pkg, m+"/")) {
+			module = m
+		}
+	}
+	if len(module) == 0 {
+		return path.Base(file)
+	}
+	return path.Join(strings.TrimPrefix(pkg[len(module):], "/"), path.Base(file))
+}
+
+// resolveCaller gets the caller information for the given program counter and source file
+func resolveCaller(pc uintptr, file string) *callerInfo {
+	if info, found := callers.Load(pc); found {
+		return info.(*callerInfo)
+	}
+	info := &callerInfo{function: "???", pkg: "???", moduleSource: path.Base(file)}
+	if f := runtime.FuncForPC(pc); f != nil {
+		info.pkg, info.function = splitFunctionName(f.Name())
+		info.moduleSource = moduleRelativeSource(info.pkg, file)
+	}
+	callers.Store(pc, info)
+	return info
+}
+
+// sourceFile returns the full path of the entry's source file, or "???" if it's unknown
+func (logEntry *LogEntry) sourceFile() string {
+	if logEntry.Source == nil {
+		return "???"
+	}
+	return *logEntry.Source
+}
+
+// captureCaller fills in the source information of the log entry with the caller at the given call depth, relative
+// to the function calling captureCaller
+func (logEntry *LogEntry) captureCaller(callDepth int) {
+	pc, file, line, ok := runtime.Caller(callDepth + 1)
+	if !ok {
+		file = "???"
+		line = 0
+		logEntry.Function = "???"
+		logEntry.Package = "???"
+		logEntry.ModuleSource = file
+	} else {
+		info := resolveCaller(pc, file)
+		logEntry.Function = info.function
+		logEntry.Package = info.pkg
+		logEntry.ModuleSource = info.moduleSource
+	}
+	logEntry.Source = &file
+	logEntry.Line = line
+}
+
+// captureCallerSkipping fills in the source information of the log entry with the first caller, from the given call
+// depth relative to the function calling captureCallerSkipping, which doesn't belong to any of the given packages
+func (logEntry *LogEntry) captureCallerSkipping(callDepth int, packages ...string) {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(callDepth+2, pcs)])
+	for {
+		frame, more := frames.Next()
+		pkg, function := splitFunctionName(frame.Function)
+		skipped := false
+		for _, p := range packages {
+			skipped = skipped || pkg == p
+		}
+		if !skipped || !more {
+			file := frame.File
+			logEntry.Source = &file
+			logEntry.Line = frame.Line
+			logEntry.Function = function
+			logEntry.Package = pkg
+			logEntry.ModuleSource = moduleRelativeSource(pkg, file)
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ContextExtractor extracts fields from a context. Registered extractors are called for every entry logged with a
+// context, adding the extracted fields to the entry (like request IDs or trace and span IDs).
+type ContextExtractor func(ctx context.Context) []Field
+
+// contextKey type of the keys used to store values in a context by the package
+type contextKey int
+
+// keys of the values stored in a context by the package
+const (
+	loggerKey contextKey = iota
+	fieldsKey
+	levelKey
+)
+
+var (
+	contextExtractors     []*ContextExtractor // registered context extractors. Pointers identify them when unregistering.
+	contextExtractorsLock = sync.RWMutex{}    // mutex to manipulate the registered context extractors
+)
+
+// NewContext returns a copy of the context holding the given logger
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the logger held by the context, or the default logger if the context holds none
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, found := ctx.Value(loggerKey).(Logger); found {
+			return logger
+		}
+	}
+	return defaultRegistry.Default()
+}
+
+// WithFields returns a copy of the context holding the given fields, on top of any fields the context already holds.
+// These fields are added to every entry logged with the context. A nil context is treated as an empty one.
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing, _ := ctx.Value(fieldsKey).([]Field)
+	return context.WithValue(ctx, fieldsKey, append(existing[:len(existing):len(existing)], fields...))
+}
+
+// WithEnabledLevel returns a copy of the context enabling entries up to the given level for every logger the context
+// is logged with, whatever the level of the logger (like enabling TRACE for a single request). Entries more severe than
+// the logger level are always logged. A nil context is treated as an empty one.
+func WithEnabledLevel(ctx context.Context, level int) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, levelKey, level)
+}
+
+// EnabledLevel returns the level enabled by the context, if any
+func EnabledLevel(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, found := ctx.Value(levelKey).(int)
+	return level, found
+}
+
+// enabled returns true if entries at the given level should be logged by a logger with the given level when logged
+// with the context
+func enabled(ctx context.Context, loggerLevel int, level int) bool {
+	if level <= loggerLevel {
+		return true
+	}
+	contextLevel, found := EnabledLevel(ctx)
+	return found && level <= contextLevel
+}
+
+// EnableLevelFromHeader decorates an http handler so that requests with the given header set to a level name (like
+// X-Log-Level: TRACE) have that level enabled in their context. As any client may set the header, it should only be
+// used behind a trusted boundary.
+func EnableLevelFromHeader(header string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if value := request.Header.Get(header); len(value) > 0 {
+			if level := LevelSeverity(strings.ToUpper(strings.TrimSpace(value))); level != UNKNOWN {
+				request = request.WithContext(WithEnabledLevel(request.Context(), level))
+			}
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// RegisterContextExtractor registers an extractor to be called for every entry logged with a context. The returned
+// function unregisters the extractor.
+func RegisterContextExtractor(extractor ContextExtractor) func() {
+	registered := &extractor
+	contextExtractorsLock.Lock()
+	defer contextExtractorsLock.Unlock()
+	contextExtractors = append(contextExtractors, registered)
+	return func() {
+		contextExtractorsLock.Lock()
+		defer contextExtractorsLock.Unlock()
+		for i, e := range contextExtractors {
+			if e == registered {
+				contextExtractors = append(contextExtractors[:i:i], contextExtractors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// recordContext adds the fields held by the context and the ones extracted from it by the registered extractors to
+// the log entry
+func (logEntry *LogEntry) recordContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if fields, found := ctx.Value(fieldsKey).([]Field); found {
+		logEntry.Fields = append(logEntry.Fields, fields...)
+	}
+	contextExtractorsLock.RLock()
+	defer contextExtractorsLock.RUnlock()
+	for _, extractor := range contextExtractors {
+		logEntry.Fields = append(logEntry.Fields, (*extractor)(ctx)...)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type requestIdKey struct{}
+
+func TestContext(t *testing.T) {
+	resetLoggers()
+
+	t.Run("Test getting logger from context", func(t *testing.T) {
+		logger, _ := Get("CONTEXT")
+		if FromContext(NewContext(context.Background(), logger)) != logger {
+			t.Error("Logger stored in context not returned")
+		}
+		if FromContext(context.Background()) != defaultRegistry.Default() {
+			t.Error("Default logger not returned for context without logger")
+		}
+	})
+
+	t.Run("Test context fields are added to entries", func(t *testing.T) {
+		unregister := RegisterContextExtractor(func(ctx context.Context) []Field {
+			if id, found := ctx.Value(requestIdKey{}).(string); found {
+				return []Field{F("request", id)}
+			}
+			return nil
+		})
+		defer unregister()
+		appender := &recordingAppender{}
+		logger, _ := newLogger("CONTEXT", SyncedAppenders().WithAppenders(appender).(*options))
+		ctx := WithFields(context.WithValue(context.Background(), requestIdKey{}, "r-1"), F("user", "u-1"))
+
+		logger.WarningCtx(ctx, "WRN", F("attempt", 1))
+		logger.Warning("WRN")
+
+		if fields := appender.entries[0].Fields; len(fields) != 3 ||
+			fields[0] != F("user", "u-1") || fields[1] != F("request", "r-1") || fields[2] != F("attempt", 1) {
+			t.Error("Unexpected fields for entry logged with context :", fields)
+		}
+		if fields := appender.entries[1].Fields; len(fields) != 0 {
+			t.Error("Unexpected fields for entry logged without context :", fields)
+		}
+
+		unregister()
+		logger.WarningCtx(ctx, "WRN")
+		if fields := appender.entries[2].Fields; len(fields) != 1 || fields[0] != F("user", "u-1") {
+			t.Error("Unexpected fields for entry logged after unregistering the extractor :", fields)
+		}
+	})
+
+	t.Run("Test adding fields to a nil context", func(t *testing.T) {
+		ctx := WithFields(nil, F("user", "u-1"))
+		if fields, _ := ctx.Value(fieldsKey).([]Field); len(fields) != 1 || fields[0] != F("user", "u-1") {
+			t.Error("Unexpected fields for nil context :", fields)
+		}
+	})
+
+	t.Run("Test context fields through default logger", func(t *testing.T) {
+		buf.Reset()
+		WarningfCtx(WithFields(context.Background(), F("user", "u-1")), "%v", "WRN")
+		if buf.String() != "WRN user=u-1\n" {
+			t.Errorf("Unexpected output: %q", buf.String())
+		}
+	})
+}
+
+func TestContextEnabledLevel(t *testing.T) {
+	appender := &recordingAppender{}
+	logger, _ := newLogger("ENABLED", SyncedAppenders().WithAppenders(appender).(*options))
+	ctx := WithEnabledLevel(context.Background(), TRACE)
+
+	t.Run("Test context enabling levels", func(t *testing.T) {
+		if logger.Enabled(DEBUG) || !logger.EnabledCtx(ctx, TRACE) || logger.EnabledCtx(context.Background(), DEBUG) {
+			t.Error("Unexpected enabled levels.")
+		}
+		logger.TraceCtx(ctx, "enabled")
+		logger.Debugf("disabled")
+		logger.DebugCtx(context.Background(), "disabled")
+		if len(appender.entries) != 1 || appender.entries[0].Message != "enabled\n" {
+			t.Error("Unexpected entries :", appender.entries)
+		}
+	})
+
+	t.Run("Test enabling levels on a nil context", func(t *testing.T) {
+		if level, found := EnabledLevel(WithEnabledLevel(nil, DEBUG)); !found || level != DEBUG {
+			t.Error("Unexpected enabled level for nil context :", level, found)
+		}
+	})
+
+	t.Run("Test enabling level from header", func(t *testing.T) {
+		var enabledLevel int
+		var found bool
+		handler := EnableLevelFromHeader("X-Log-Level", http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+			enabledLevel, found = EnabledLevel(request.Context())
+		}))
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-Log-Level", "debug")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		if !found || enabledLevel != DEBUG {
+			t.Error("Level not enabled from header :", enabledLevel, found)
+		}
+		request.Header.Set("X-Log-Level", "verbose")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		if found {
+			t.Error("Unknown level names should not enable a level.")
+		}
+	})
+}
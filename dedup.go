@@ -0,0 +1,139 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// RepeatedKey is the key of the field holding the number of collapsed repetitions in a deduplication entry
+const RepeatedKey = "repeated"
+
+// dedupKey identifies repeated entries
+type dedupKey struct {
+	level   int
+	message string
+}
+
+// repetition keeps track of the repetitions of an entry within a deduplication window
+type repetition struct {
+	count int       // number of repetitions since the first entry of the window
+	last  *LogEntry // last repeated entry
+}
+
+// deduplicator collapses entries with the same level and message within a window. The first entry is let through,
+// the repetitions are reported as a single entry with the repetition count when the window closes.
+type deduplicator struct {
+	window      time.Duration            // duration of the deduplication window, starting with the first entry
+	emit        func(entry *LogEntry)    // function outputting the collapsed repetitions entry
+	repetitions map[dedupKey]*repetition // repetitions of the entries in an open window
+	mutex       sync.Mutex
+}
+
+// newDeduplicator creates a deduplicator with the given window, or nil if the window is not positive
+func newDeduplicator(window time.Duration, emit func(entry *LogEntry)) *deduplicator {
+	if window <= 0 {
+		return nil
+	}
+	return &deduplicator{
+		window:      window,
+		emit:        emit,
+		repetitions: make(map[dedupKey]*repetition),
+	}
+}
+
+// filter returns true if the entry is the first one with its level and message in the current window
+func (d *deduplicator) filter(entry *LogEntry) bool {
+	if d == nil {
+		return true
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	key := dedupKey{level: entry.Level, message: entry.Message}
+	if r, found := d.repetitions[key]; found {
+		r.count++
+		r.last = entry
+		return false
+	}
+	d.repetitions[key] = &repetition{}
+	time.AfterFunc(d.window, func() {
+		d.close(key)
+	})
+	return true
+}
+
+// close closes the window of an entry, emitting the collapsed repetitions if any
+func (d *deduplicator) close(key dedupKey) {
+	d.mutex.Lock()
+	r, found := d.repetitions[key]
+	delete(d.repetitions, key)
+	d.mutex.Unlock()
+
+	if found && r.count > 0 {
+		entry := *r.last
+		entry.Fields = append(entry.Fields[:len(entry.Fields):len(entry.Fields)], F(RepeatedKey, r.count))
+		d.emit(&entry)
+	}
+}
+
+// flush closes all open windows, emitting the collapsed repetitions without waiting for the windows to elapse
+func (d *deduplicator) flush() {
+	if d == nil {
+		return
+	}
+	d.mutex.Lock()
+	keys := make([]dedupKey, 0, len(d.repetitions))
+	for key := range d.repetitions {
+		keys = append(keys, key)
+	}
+	d.mutex.Unlock()
+
+	for _, key := range keys {
+		d.close(key)
+	}
+}
+
+// dedupAppender appender decorator collapsing repeated entries
+type dedupAppender struct {
+	appender     Appender
+	deduplicator *deduplicator
+	mutex        sync.Mutex
+}
+
+// Deduplicate decorates an appender so that entries with the same level and message within the window are collapsed
+// into the first entry and a single entry, emitted when the window closes, with the repetition count in its
+// RepeatedKey field. Collapsed entries are emitted from a different goroutine, calls to the decorated appender are
+// serialized by the decorator.
+func Deduplicate(appender Appender, window time.Duration) Appender {
+	da := &dedupAppender{appender: appender}
+	da.deduplicator = newDeduplicator(window, da.print)
+	return da
+}
+
+// Print hands the entry to the decorated appender unless it's a repetition
+func (da *dedupAppender) Print(entry *LogEntry) {
+	if da.deduplicator.filter(entry) {
+		da.print(entry)
+	}
+}
+
+// print hands the entry to the decorated appender
+func (da *dedupAppender) print(entry *LogEntry) {
+	da.mutex.Lock()
+	defer da.mutex.Unlock()
+	da.appender.Print(entry)
+}
+
+// decorated returns the appender decorated by the deduplication
+func (da *dedupAppender) decorated() Appender {
+	return da.appender
+}
+
+// flush emits the repetitions collapsed in the open windows
+func (da *dedupAppender) flush() {
+	da.deduplicator.flush()
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeduplication(t *testing.T) {
+	t.Run("Test deduplicating appender", func(t *testing.T) {
+		appender := &lockedAppender{}
+		deduplicating := Deduplicate(appender, time.Hour)
+		logger, _ := newLogger("DEDUP", SyncedAppenders().WithAppenders(deduplicating).(*options))
+
+		for i := 0; i < 5; i++ {
+			logger.Errorf("retrying %s", "connection")
+		}
+		logger.Error("other")
+		if messages := appender.messages(); len(messages) != 2 || messages[0] != "retrying connection" || messages[1] != "other\n" {
+			t.Error("Unexpected entries during deduplication window :", messages)
+		}
+
+		deduplicating.(flusher).flush()
+		appender.mutex.Lock()
+		defer appender.mutex.Unlock()
+		if len(appender.entries) != 3 {
+			t.Fatal("Collapsed repetitions not emitted :", len(appender.entries))
+		}
+		repeated := appender.entries[2]
+		if repeated.Message != "retrying connection" || len(repeated.Fields) != 1 || repeated.Fields[0] != F(RepeatedKey, 4) {
+			t.Error("Unexpected collapsed repetitions entry :", repeated)
+		}
+	})
+
+	t.Run("Test deduplicating logger option", func(t *testing.T) {
+		buffer := &lockedBuffer{}
+		logger, _ := newLogger("DEDUP", Standard().WithWriter(buffer).WithDeduplication(time.Hour).(*options))
+
+		for i := 0; i < 3; i++ {
+			logger.Warning("WRN")
+		}
+		if output := buffer.String(); output != "WRN\n" {
+			t.Errorf("Unexpected output during deduplication window: %q", output)
+		}
+		logger.(managedLogger).flush()
+		if output := buffer.String(); output != "WRN\nWRN repeated=2\n" {
+			t.Errorf("Unexpected deduplicated output: %q", output)
+		}
+	})
+
+	t.Run("Test repetitions emitted when window closes", func(t *testing.T) {
+		buffer := &lockedBuffer{}
+		logger, _ := newLogger("DEDUP", Standard().WithWriter(buffer).WithDeduplication(100*time.Millisecond).(*options))
+
+		logger.Warning("WRN")
+		logger.Warning("WRN")
+		if !eventually(func() bool { return buffer.String() == "WRN\nWRN repeated=1\n" }) {
+			t.Errorf("Unexpected deduplicated output: %q", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonEntry is the JSON representation of a log entry
+type jsonEntry struct {
+	Timestamp    time.Time                  `json:"timestamp"`
+	Logger       string                     `json:"logger,omitempty"`
+	Level        string                     `json:"level"`
+	Severity     int                        `json:"severity"`
+	Message      string                     `json:"message"`
+	Source       string                     `json:"source,omitempty"`
+	Line         int                        `json:"line,omitempty"`
+	Function     string                     `json:"function,omitempty"`
+	Package      string                     `json:"package,omitempty"`
+	ModuleSource string                     `json:"moduleSource,omitempty"`
+	Fields       map[string]json.RawMessage `json:"fields,omitempty"`
+	Errors       []ErrorInfo                `json:"errors,omitempty"`
+	Stack        []StackFrame               `json:"stack,omitempty"`
+}
+
+// jsonValue encodes a field value as JSON. Errors are encoded as their message and values which can't be encoded
+// as JSON are encoded as their default format string.
+func jsonValue(value interface{}) json.RawMessage {
+	if e, isError := value.(error); isError && e != nil {
+		value = e.Error()
+	}
+	if encoded, e := json.Marshal(value); e == nil {
+		return encoded
+	}
+	return strconv.AppendQuote(nil, fmt.Sprint(value))
+}
+
+// JSONEncoder encodes entries as single line JSON objects (NDJSON)
+type JSONEncoder struct{}
+
+// Encode appends the entry as a JSON object followed by a new line
+func (JSONEncoder) Encode(buf []byte, entry *LogEntry) []byte {
+	view := &jsonEntry{
+		Timestamp:    entry.Timestamp,
+		Logger:       entry.Logger,
+		Level:        LevelName(entry.Level),
+		Severity:     entry.Level,
+		Message:      strings.TrimSuffix(entry.Message, "\n"),
+		Line:         entry.Line,
+		Function:     entry.Function,
+		Package:      entry.Package,
+		ModuleSource: entry.ModuleSource,
+		Errors:       entry.Errors,
+		Stack:        entry.Stack,
+	}
+	if entry.Source != nil {
+		view.Source = *entry.Source
+	}
+	if len(entry.Fields) > 0 {
+		view.Fields = make(map[string]json.RawMessage, len(entry.Fields))
+		for _, field := range entry.Fields {
+			view.Fields[field.Key] = jsonValue(field.Value)
+		}
+	}
+	encoded, _ := json.Marshal(view)
+	return append(append(buf, encoded...), '\n')
+}
+
+// TextEncoder encodes entries as text lines like the standard writer logger
+type TextEncoder struct {
+	Prefix           []uint // header format of the lines (same flags as WithLogPrefix)
+	DateFlags        int    // format flags of the Time header flag
+	StackTraceFormat uint   // format used to write stack traces
+}
+
+// Encode appends the entry as a text line, followed by its stack trace if it has one
+func (te TextEncoder) Encode(buf []byte, entry *LogEntry) []byte {
+	formatEntry(&buf, entry, te.Prefix, te.DateFlags, te.StackTraceFormat)
+	return buf
+}
@@ -16,9 +16,24 @@ const (
 	// ErrReinitializingExistingLogger Error raised when trying to initialize an existing logger with different options
 	ErrReinitializingExistingLogger = err.Error("trying to initialize an already initialized logger with different options")
 
+	// ErrRemovingDefaultLogger Error raised when trying to remove the default logger from a registry
+	ErrRemovingDefaultLogger = err.Error("the default logger can't be removed")
+
 	// ErrLoggerDoesNotExist Error raised when referring to a non-existing logger
 	ErrLoggerDoesNotExist = err.Error("logger with given name doesn't exist")
 
+	// ErrUnsupportedByLoggerType Error raised when changing a setting a logger of that type doesn't have
+	ErrUnsupportedByLoggerType = err.Error("setting is not supported by the logger type")
+
 	// ErrUnknownLoggerType Error raised when creating a new logger of an unknown type (shouldn't happen)
 	ErrUnknownLoggerType = err.Error("logger type is not known")
+
+	// ErrInvalidVModule Error raised when parsing an invalid pattern=verbosity pair of a vmodule specification
+	ErrInvalidVModule = err.ErrorF("invalid vmodule pattern=verbosity pair %q")
+
+	// ErrUnsupportedJournalTransfer Error raised when an entry too big for a datagram can't be passed to the journal
+	ErrUnsupportedJournalTransfer = err.Error("journal entries can't be passed through files")
+
+	// ErrIngestionFailed Error raised when a log ingestion endpoint rejects a batch of entries
+	ErrIngestionFailed = err.ErrorF("log ingestion endpoint responded with %s")
 )
@@ -5,6 +5,8 @@
 package log_test
 
 import (
+	"fmt"
+
 	"github.com/gomatbase/go-log"
 )
 
@@ -218,8 +220,8 @@ func ExampleSetDefaultLogger() {
 	log.Tracef("%v", "TRC")
 
 	// Output:
-	// DEFAULT example_test.go:213 - CRT
-	// DEFAULT example_test.go:214 - ERR
+	// DEFAULT example_test.go:215 - CRT
+	// DEFAULT example_test.go:216 - ERR
 }
 
 func ExampleStandard() {
@@ -282,3 +284,31 @@ func ExampleStandard() {
 	// WRN - ERR
 	// WRN - WRN
 }
+
+type functionAppender struct{}
+
+func (functionAppender) Print(entry *log.LogEntry) {
+	fmt.Println(entry.Package, entry.Function, entry.ModuleSource, entry.Line, entry.Message)
+}
+
+func ExampleSyncedAppenders() {
+	logger, _ := log.GetWithOptions("APPENDERS", log.SyncedAppenders().WithAppenders(functionAppender{}))
+
+	logger.Warningf("%v", "WRN")
+
+	// Output:
+	// github.com/gomatbase/go-log_test ExampleSyncedAppenders example_test.go 297 WRN
+}
+
+func Example_callerPrefix() {
+	logger, _ := log.GetWithOptions("CALLER", log.Standard().WithLogPrefix(log.Package, log.Function, log.ModuleSource, log.Separator))
+
+	logger.Warning("WRN")
+	func() {
+		logger.Error("ERR")
+	}()
+
+	// Output:
+	// github.com/gomatbase/go-log_test Example_callerPrefix example_test.go:306 - WRN
+	// github.com/gomatbase/go-log_test Example_callerPrefix.func1 example_test.go:308 - ERR
+}
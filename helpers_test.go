@@ -0,0 +1,61 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// eventually polls the condition until it holds, returning false if it still doesn't after a few seconds
+func eventually(condition func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+type lockedAppender struct {
+	recordingAppender
+	mutex sync.Mutex
+}
+
+func (la *lockedAppender) Print(entry *LogEntry) {
+	la.mutex.Lock()
+	defer la.mutex.Unlock()
+	la.recordingAppender.Print(entry)
+}
+
+func (la *lockedAppender) messages() []string {
+	la.mutex.Lock()
+	defer la.mutex.Unlock()
+	messages := make([]string, len(la.entries))
+	for i, entry := range la.entries {
+		messages[i] = entry.Message
+	}
+	return messages
+}
+
+type lockedBuffer struct {
+	buffer bytes.Buffer
+	mutex  sync.Mutex
+}
+
+func (lb *lockedBuffer) Write(p []byte) (int, error) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.Write(p)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.String()
+}
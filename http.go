@@ -0,0 +1,315 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Batch body formats of the http appender
+const (
+	BatchNDJSON    = iota // one encoded entry per line (default)
+	BatchJSONArray        // JSON array of encoded entries
+)
+
+// Default http appender settings
+const (
+	DefaultBatchCount     = 100
+	DefaultBatchBytes     = 1 << 20
+	DefaultFlushInterval  = 5 * time.Second
+	DefaultMaxRetries     = 3
+	DefaultPendingBatches = 16
+	DefaultCloseTimeout   = 10 * time.Second
+)
+
+// NoRetries disables the retries of failing batches when used as MaxRetries of an http appender
+const NoRetries = -1
+
+// HTTPConfig holds the configuration of an http batch appender
+type HTTPConfig struct {
+	URL            string        // URL of the ingestion endpoint
+	Format         int           // format of the batch body, BatchNDJSON or BatchJSONArray
+	Encoder        Encoder       // encoder of the entries, must produce JSON for BatchJSONArray (defaults to JSONEncoder)
+	Headers        http.Header   // headers added to every request (like authorization tokens)
+	Gzip           bool          // compress the request bodies with gzip
+	MaxBatchCount  int           // number of entries triggering a batch to be sent (defaults to DefaultBatchCount)
+	MaxBatchBytes  int           // encoded size triggering a batch to be sent (defaults to DefaultBatchBytes)
+	FlushInterval  time.Duration // interval after which a partial batch is sent (defaults to DefaultFlushInterval)
+	MaxRetries     int           // retries of a batch failing with 429, 5xx or a network error (defaults to DefaultMaxRetries, NoRetries or any negative value disables them)
+	MinBackoff     time.Duration // initial delay between retries (defaults to DefaultMinBackoff)
+	MaxBackoff     time.Duration // maximum delay between retries (defaults to DefaultMaxBackoff)
+	PendingBatches int           // batches waiting to be sent before new ones are dropped (defaults to DefaultPendingBatches)
+	Client         *http.Client  // client used for the requests (defaults to a client with a 30 seconds timeout)
+	CloseTimeout   time.Duration // time given to send the pending batches, including retries, when closing (defaults to DefaultCloseTimeout)
+}
+
+// HTTPAppender appender sending batches of encoded entries to an http ingestion endpoint. Batches are sent by a
+// background goroutine when reaching the configured count or size, or when the flush interval elapses.
+type HTTPAppender struct {
+	config     HTTPConfig
+	envelope   [2][]byte          // prefix and suffix wrapping the JSON array of entries in the body of a request
+	batch      [][]byte           // entries of the batch being filled
+	batchBytes int                // encoded size of the batch being filled
+	batches    chan [][]byte      // full batches waiting to be sent
+	dropped    uint64             // number of entries dropped, either because too many batches were pending or failed to be sent
+	lastError  error              // error of the last batch failing to be sent
+	done       chan struct{}      // closed when the appender is closed
+	drain      context.Context    // canceled once the close timeout elapses, interrupting requests and retries
+	expire     context.CancelFunc // cancels the drain
+	stopped    chan struct{}      // closed when the sender finishes
+	closed     bool
+	mutex      sync.Mutex
+}
+
+// NewHTTPAppender creates an http batch appender
+func NewHTTPAppender(config HTTPConfig) *HTTPAppender {
+	return newHTTPAppender(config, [2][]byte{})
+}
+
+// newHTTPAppender creates an http batch appender wrapping JSON arrays of entries with the given envelope
+func newHTTPAppender(config HTTPConfig, envelope [2][]byte) *HTTPAppender {
+	if config.Encoder == nil {
+		config.Encoder = JSONEncoder{}
+	}
+	if config.MaxBatchCount <= 0 {
+		config.MaxBatchCount = DefaultBatchCount
+	}
+	if config.MaxBatchBytes <= 0 {
+		config.MaxBatchBytes = DefaultBatchBytes
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = DefaultMinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.PendingBatches <= 0 {
+		config.PendingBatches = DefaultPendingBatches
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if config.CloseTimeout <= 0 {
+		config.CloseTimeout = DefaultCloseTimeout
+	}
+	appender := &HTTPAppender{
+		config:   config,
+		envelope: envelope,
+		batches:  make(chan [][]byte, config.PendingBatches),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	appender.drain, appender.expire = context.WithCancel(context.Background())
+	go appender.send()
+	return appender
+}
+
+// Print encodes the entry and adds it to the current batch, queueing the batch to be sent if it's full
+func (ha *HTTPAppender) Print(entry *LogEntry) {
+	encoded := ha.config.Encoder.Encode(nil, entry)
+
+	ha.mutex.Lock()
+	defer ha.mutex.Unlock()
+	if ha.closed {
+		atomic.AddUint64(&ha.dropped, 1)
+		return
+	}
+	ha.batch = append(ha.batch, encoded)
+	ha.batchBytes += len(encoded)
+	if len(ha.batch) >= ha.config.MaxBatchCount || ha.batchBytes >= ha.config.MaxBatchBytes {
+		select {
+		case ha.batches <- ha.batch:
+		default:
+			atomic.AddUint64(&ha.dropped, uint64(len(ha.batch)))
+		}
+		ha.batch = nil
+		ha.batchBytes = 0
+	}
+}
+
+// Dropped returns the number of entries dropped, either because too many batches were pending or because they
+// failed to be sent
+func (ha *HTTPAppender) Dropped() uint64 {
+	return atomic.LoadUint64(&ha.dropped)
+}
+
+// Close stops accepting entries and sends all pending entries before returning, still retrying failing batches until
+// the close timeout elapses. Entries not sent by then are dropped. The error of the last batch which failed to be sent
+// is returned, if any.
+func (ha *HTTPAppender) Close() error {
+	ha.mutex.Lock()
+	if !ha.closed {
+		ha.closed = true
+		close(ha.done)
+		time.AfterFunc(ha.config.CloseTimeout, ha.expire)
+	}
+	ha.mutex.Unlock()
+
+	<-ha.stopped
+	ha.expire()
+	ha.mutex.Lock()
+	defer ha.mutex.Unlock()
+	return ha.lastError
+}
+
+// takeBatch takes the batch being filled
+func (ha *HTTPAppender) takeBatch() [][]byte {
+	ha.mutex.Lock()
+	defer ha.mutex.Unlock()
+	batch := ha.batch
+	ha.batch = nil
+	ha.batchBytes = 0
+	return batch
+}
+
+// send is the sender loop, sending full batches as they are queued and partial batches on every flush interval
+func (ha *HTTPAppender) send() {
+	defer close(ha.stopped)
+	ticker := time.NewTicker(ha.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case batch := <-ha.batches:
+			ha.post(batch)
+		case <-ticker.C:
+			if batch := ha.takeBatch(); len(batch) > 0 {
+				ha.post(batch)
+			}
+		case <-ha.done:
+			for {
+				select {
+				case batch := <-ha.batches:
+					ha.post(batch)
+				default:
+					if batch := ha.takeBatch(); len(batch) > 0 {
+						ha.post(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// body builds the request body for a batch
+func (ha *HTTPAppender) body(batch [][]byte) []byte {
+	buffer := &bytes.Buffer{}
+	var writer io.Writer = buffer
+	var compressor *gzip.Writer
+	if ha.config.Gzip {
+		compressor = gzip.NewWriter(buffer)
+		writer = compressor
+	}
+	if ha.config.Format == BatchJSONArray {
+		_, _ = writer.Write(ha.envelope[0])
+		_, _ = writer.Write([]byte{'['})
+		for i, encoded := range batch {
+			if i > 0 {
+				_, _ = writer.Write([]byte{','})
+			}
+			_, _ = writer.Write(bytes.TrimSuffix(encoded, []byte{'\n'}))
+		}
+		_, _ = writer.Write([]byte{']'})
+		_, _ = writer.Write(ha.envelope[1])
+	} else {
+		for _, encoded := range batch {
+			_, _ = writer.Write(encoded)
+		}
+	}
+	if compressor != nil {
+		_ = compressor.Close()
+	}
+	return buffer.Bytes()
+}
+
+// post sends a batch to the endpoint, retrying with exponential backoff on network errors, 429 and 5xx responses.
+// Failing batches are dropped. Once the close timeout elapses, batches are no longer retried.
+func (ha *HTTPAppender) post(batch [][]byte) {
+	body := ha.body(batch)
+	backoff := ha.config.MinBackoff
+	var e error
+retries:
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		if retryAfter, e = ha.request(body); e == nil {
+			return
+		} else if retryAfter < 0 || attempt >= ha.config.MaxRetries {
+			break
+		}
+		if retryAfter == 0 {
+			retryAfter = backoff
+		} else if retryAfter > ha.config.MaxBackoff {
+			retryAfter = ha.config.MaxBackoff
+		}
+		select {
+		case <-ha.drain.Done():
+			break retries
+		case <-time.After(retryAfter):
+		}
+		if backoff *= 2; backoff > ha.config.MaxBackoff {
+			backoff = ha.config.MaxBackoff
+		}
+	}
+	atomic.AddUint64(&ha.dropped, uint64(len(batch)))
+	ha.mutex.Lock()
+	ha.lastError = e
+	ha.mutex.Unlock()
+}
+
+// request posts a body to the endpoint. When failing, it returns a negative delay if the request should not be
+// retried, or the delay requested by the server through Retry-After if any.
+func (ha *HTTPAppender) request(body []byte) (time.Duration, error) {
+	request, e := http.NewRequestWithContext(ha.drain, http.MethodPost, ha.config.URL, bytes.NewReader(body))
+	if e != nil {
+		return -1, e
+	}
+	for name, values := range ha.config.Headers {
+		request.Header[name] = values
+	}
+	if len(request.Header.Get("Content-Type")) == 0 {
+		if ha.config.Format == BatchJSONArray {
+			request.Header.Set("Content-Type", "application/json")
+		} else {
+			request.Header.Set("Content-Type", "application/x-ndjson")
+		}
+	}
+	if ha.config.Gzip {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+
+	response, e := ha.config.Client.Do(request)
+	if e != nil {
+		return 0, e
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return 0, nil
+	}
+	e = ErrIngestionFailed.WithValues(response.Status)
+	if response.StatusCode != http.StatusTooManyRequests && response.StatusCode < 500 {
+		return -1, e
+	}
+	if seconds, parseError := strconv.Atoi(response.Header.Get("Retry-After")); parseError == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, e
+	}
+	return 0, e
+}
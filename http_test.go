@@ -0,0 +1,189 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ingestionServer test server recording the bodies of the requests it receives
+type ingestionServer struct {
+	*httptest.Server
+	failures int // number of requests to fail with 503 before accepting them
+	bodies   []string
+	headers  []http.Header
+	mutex    sync.Mutex
+}
+
+func newIngestionServer(failures int) *ingestionServer {
+	server := &ingestionServer{failures: failures}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		if server.failures > 0 {
+			server.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			reader, _ = gzip.NewReader(r.Body)
+		}
+		body, _ := io.ReadAll(reader)
+		server.bodies = append(server.bodies, string(body))
+		server.headers = append(server.headers, r.Header)
+	}))
+	return server
+}
+
+func TestHTTPAppender(t *testing.T) {
+	t.Run("Test NDJSON batches by count", func(t *testing.T) {
+		server := newIngestionServer(0)
+		defer server.Close()
+
+		appender := NewHTTPAppender(HTTPConfig{URL: server.URL, MaxBatchCount: 2, FlushInterval: time.Hour})
+		for _, message := range []string{"first", "second", "third"} {
+			appender.Print(&LogEntry{Level: INFO, Message: message})
+		}
+		if e := appender.Close(); e != nil {
+			t.Error("Unexpected error closing appender :", e)
+		}
+
+		if len(server.bodies) != 2 || strings.Count(server.bodies[0], "\n") != 2 || strings.Count(server.bodies[1], "\n") != 1 {
+			t.Fatalf("Unexpected batches: %q", server.bodies)
+		}
+		if !strings.Contains(server.bodies[1], `"message":"third"`) || server.headers[0].Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("Unexpected batch: %q", server.bodies[1])
+		}
+	})
+
+	t.Run("Test gzipped JSON array with headers and retries", func(t *testing.T) {
+		server := newIngestionServer(2)
+		defer server.Close()
+
+		headers := http.Header{}
+		headers.Set("Authorization", "Splunk token")
+		appender := NewHTTPAppender(HTTPConfig{
+			URL:           server.URL,
+			Format:        BatchJSONArray,
+			Gzip:          true,
+			Headers:       headers,
+			FlushInterval: 10 * time.Millisecond,
+			MinBackoff:    time.Millisecond,
+		})
+		defer appender.Close()
+		appender.Print(&LogEntry{Level: ERROR, Message: "first"})
+		appender.Print(&LogEntry{Level: ERROR, Message: "second"})
+
+		time.Sleep(100 * time.Millisecond)
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		if len(server.bodies) != 1 {
+			t.Fatalf("Unexpected batches: %q", server.bodies)
+		}
+		var entries []map[string]interface{}
+		if e := json.Unmarshal([]byte(server.bodies[0]), &entries); e != nil || len(entries) != 2 || entries[1]["message"] != "second" {
+			t.Errorf("Unexpected JSON array batch %q (error %v)", server.bodies[0], e)
+		}
+		if server.headers[0].Get("Authorization") != "Splunk token" {
+			t.Error("Custom header not sent")
+		}
+	})
+
+	t.Run("Test failing batches are dropped", func(t *testing.T) {
+		server := newIngestionServer(10)
+		defer server.Close()
+
+		appender := NewHTTPAppender(HTTPConfig{URL: server.URL, MaxRetries: 1, MinBackoff: time.Millisecond})
+		appender.Print(&LogEntry{Level: ERROR, Message: "lost"})
+		if e := appender.Close(); !ErrIngestionFailed.IsKindOf(e) {
+			t.Error("Unexpected error closing appender :", e)
+		}
+		if appender.Dropped() != 1 {
+			t.Error("Unexpected number of dropped entries :", appender.Dropped())
+		}
+	})
+	t.Run("Test close timeout interrupts retries", func(t *testing.T) {
+		server := newIngestionServer(10)
+		defer server.Close()
+
+		appender := NewHTTPAppender(HTTPConfig{URL: server.URL, MaxBatchCount: 1, MinBackoff: time.Hour, MaxBackoff: time.Hour,
+			CloseTimeout: 50 * time.Millisecond})
+		appender.Print(&LogEntry{Level: ERROR, Message: "lost"})
+		if !eventually(func() bool {
+			server.mutex.Lock()
+			defer server.mutex.Unlock()
+			return server.failures < 10
+		}) {
+			t.Fatal("Batch not sent")
+		}
+
+		closed := make(chan error)
+		go func() {
+			closed <- appender.Close()
+		}()
+		select {
+		case e := <-closed:
+			if !ErrIngestionFailed.IsKindOf(e) {
+				t.Error("Unexpected error closing appender :", e)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Closing the appender blocked by the retry backoff beyond the close timeout")
+		}
+		if appender.Dropped() != 1 {
+			t.Error("Unexpected number of dropped entries :", appender.Dropped())
+		}
+	})
+
+	t.Run("Test disabled retries", func(t *testing.T) {
+		server := newIngestionServer(1)
+		defer server.Close()
+
+		appender := NewHTTPAppender(HTTPConfig{URL: server.URL, MaxRetries: NoRetries})
+		appender.Print(&LogEntry{Level: ERROR, Message: "lost"})
+		if e := appender.Close(); !ErrIngestionFailed.IsKindOf(e) || appender.Dropped() != 1 {
+			t.Error("Failing batch retried :", e)
+		}
+	})
+	t.Run("Test closing retries pending batches", func(t *testing.T) {
+		server := newIngestionServer(2)
+		defer server.Close()
+
+		appender := NewHTTPAppender(HTTPConfig{URL: server.URL, MinBackoff: 10 * time.Millisecond})
+		appender.Print(&LogEntry{Level: ERROR, Message: "drained"})
+		if e := appender.Close(); e != nil || appender.Dropped() != 0 {
+			t.Error("Pending batch not retried while closing :", e, appender.Dropped())
+		}
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		if len(server.bodies) != 1 || !strings.Contains(server.bodies[0], "drained") {
+			t.Errorf("Unexpected batches: %q", server.bodies)
+		}
+	})
+
+	t.Run("Test custom content type", func(t *testing.T) {
+		server := newIngestionServer(0)
+		defer server.Close()
+
+		headers := http.Header{}
+		headers.Set("Content-Type", "application/json; charset=utf-8")
+		appender := NewHTTPAppender(HTTPConfig{URL: server.URL, Headers: headers})
+		appender.Print(&LogEntry{Level: ERROR, Message: "typed"})
+		_ = appender.Close()
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		if len(server.headers) != 1 || server.headers[0].Get("Content-Type") != "application/json; charset=utf-8" {
+			t.Error("Custom content type not kept :", server.headers)
+		}
+	})
+}
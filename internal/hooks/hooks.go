@@ -0,0 +1,11 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+// Package hooks exposes internals of the log package to its sibling packages (like logtest) without making them part
+// of the log package API. The hooks are set by the log package when initialized.
+package hooks
+
+// SetDefaultLogger replaces the default logger of the package's default registry with the given logger instance,
+// returning the replaced one. Values are log.Logger, typed as interface{} as this package can't import log.
+var SetDefaultLogger func(logger interface{}) interface{}
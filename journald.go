@@ -0,0 +1,188 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default journald appender settings
+const (
+	DefaultJournalSocket        = "/run/systemd/journal/socket" // path of the journal's native protocol socket
+	DefaultJournalRetryInterval = 10 * time.Second              // delay between attempts to connect to the journal
+)
+
+// reservedJournalFields journal fields set by the appender, entry fields with the same name are prefixed with FIELD_
+var reservedJournalFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+}
+
+// JournaldConfig holds the configuration of a journald appender
+type JournaldConfig struct {
+	SocketPath    string        // path of the journal socket (defaults to DefaultJournalSocket)
+	Identifier    string        // SYSLOG_IDENTIFIER of the entries (defaults to the name of the logger)
+	Fallback      io.Writer     // writer used when the journal is not available (defaults to stderr)
+	RetryInterval time.Duration // delay between attempts to connect to the journal (defaults to DefaultJournalRetryInterval)
+}
+
+// JournaldAppender appender writing entries to the systemd journal using its native protocol. When the journal
+// socket is not available, entries are written to the fallback writer prefixed with their syslog severity ("<3>"),
+// which systemd understands when capturing the output of a service. Connecting to the journal is retried when
+// entries are printed, at most once per retry interval. Entries too big to fit in a datagram are passed to the journal
+// through a temporary file, as done by sd_journal_send.
+type JournaldAppender struct {
+	config     JournaldConfig
+	connection net.Conn
+	retry      time.Time // time after which connecting to the journal may be retried
+	closed     bool
+	buffer     []byte
+	mutex      sync.Mutex
+}
+
+// NewJournaldAppender creates a journald appender. Failing to connect to the journal socket is not an error, the
+// appender writes to the fallback writer instead.
+func NewJournaldAppender(config JournaldConfig) *JournaldAppender {
+	if len(config.SocketPath) == 0 {
+		config.SocketPath = DefaultJournalSocket
+	}
+	if config.Fallback == nil {
+		config.Fallback = os.Stderr
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultJournalRetryInterval
+	}
+	appender := &JournaldAppender{config: config}
+	appender.connect()
+	return appender
+}
+
+// Print writes the entry to the journal, or to the fallback writer if the journal is not available
+func (ja *JournaldAppender) Print(entry *LogEntry) {
+	ja.mutex.Lock()
+	defer ja.mutex.Unlock()
+
+	if ja.connection != nil || ja.connect() {
+		ja.buffer = ja.buffer[:0]
+		ja.serialize(entry)
+		_, e := ja.connection.Write(ja.buffer)
+		if e != nil && oversizedDatagram(e) {
+			e = sendJournalFile(ja.connection, ja.buffer)
+		}
+		if e == nil {
+			return
+		}
+		_ = ja.connection.Close()
+		ja.connection = nil
+		ja.retry = time.Now().Add(ja.config.RetryInterval)
+	}
+
+	ja.buffer = append(ja.buffer[:0], '<')
+	ja.buffer = strconv.AppendInt(ja.buffer, int64(syslogSeverity(entry.Level)), 10)
+	ja.buffer = append(ja.buffer, '>')
+	ja.buffer = append(ja.buffer, strings.TrimSuffix(entry.Message, "\n")...)
+	for _, field := range entry.Fields {
+		fieldtobuf(&ja.buffer, field)
+	}
+	ja.buffer = append(ja.buffer, '\n')
+	_, _ = ja.config.Fallback.Write(ja.buffer)
+}
+
+// Close closes the connection to the journal. Entries printed afterwards are written to the fallback writer.
+func (ja *JournaldAppender) Close() error {
+	ja.mutex.Lock()
+	defer ja.mutex.Unlock()
+	ja.closed = true
+	if ja.connection == nil {
+		return nil
+	}
+	e := ja.connection.Close()
+	ja.connection = nil
+	return e
+}
+
+// connect connects to the journal unless closed or waiting to retry, returning true if connected. Must be called while
+// holding the appender's lock.
+func (ja *JournaldAppender) connect() bool {
+	if ja.closed || time.Now().Before(ja.retry) {
+		return false
+	}
+	connection, e := net.Dial("unixgram", ja.config.SocketPath)
+	if e != nil {
+		ja.retry = time.Now().Add(ja.config.RetryInterval)
+		return false
+	}
+	ja.connection = connection
+	return true
+}
+
+// serialize serializes the entry into the appender's buffer in the journal's native format
+func (ja *JournaldAppender) serialize(entry *LogEntry) {
+	ja.appendField("MESSAGE", strings.TrimSuffix(entry.Message, "\n"))
+	ja.appendField("PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	identifier := ja.config.Identifier
+	if len(identifier) == 0 {
+		identifier = entry.Logger
+	}
+	ja.appendField("SYSLOG_IDENTIFIER", identifier)
+	if entry.Source != nil {
+		ja.appendField("CODE_FILE", *entry.Source)
+		ja.appendField("CODE_LINE", strconv.Itoa(entry.Line))
+		if len(entry.Package) > 0 {
+			ja.appendField("CODE_FUNC", entry.Package+"."+entry.Function)
+		} else {
+			ja.appendField("CODE_FUNC", entry.Function)
+		}
+	}
+	for _, field := range entry.Fields {
+		name := journalFieldName(field.Key)
+		if reservedJournalFields[name] {
+			name = "FIELD_" + name
+		}
+		ja.appendField(name, fmt.Sprint(field.Value))
+	}
+}
+
+// appendField appends a field to the appender's buffer. Values holding new lines are written with their binary
+// length as expected by the journal protocol.
+func (ja *JournaldAppender) appendField(name string, value string) {
+	ja.buffer = append(ja.buffer, name...)
+	if strings.IndexByte(value, '\n') < 0 {
+		ja.buffer = append(ja.buffer, '=')
+	} else {
+		ja.buffer = append(ja.buffer, '\n')
+		ja.buffer = binary.LittleEndian.AppendUint64(ja.buffer, uint64(len(value)))
+	}
+	ja.buffer = append(ja.buffer, value...)
+	ja.buffer = append(ja.buffer, '\n')
+}
+
+// journalFieldName converts a field key into a valid journal field name: upper-cased, with characters other than
+// letters, digits and '_' replaced by '_', and not starting with '_' or a digit
+func journalFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	result := strings.TrimLeft(string(name), "_")
+	if len(result) == 0 || (result[0] >= '0' && result[0] <= '9') {
+		result = "FIELD_" + result
+	}
+	return result
+}
@@ -0,0 +1,19 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+//go:build !unix
+
+package log
+
+import "net"
+
+// oversizedDatagram returns false, journal entries can't be passed through files on this platform
+func oversizedDatagram(error) bool {
+	return false
+}
+
+// sendJournalFile always fails, journal entries can't be passed through files on this platform
+func sendJournalFile(net.Conn, []byte) error {
+	return ErrUnsupportedJournalTransfer
+}
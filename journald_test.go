@@ -0,0 +1,108 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournaldAppender(t *testing.T) {
+	source := "/src/db.go"
+	entry := &LogEntry{
+		Logger:   "DB",
+		Level:    WARNING,
+		Message:  "slow query\n",
+		Source:   &source,
+		Line:     12,
+		Function: "(*Pool).Query",
+		Package:  "example.com/db",
+		Fields:   []Field{F("query-id", 7), F("sql", "SELECT 1\nFROM dual")},
+	}
+
+	t.Run("Test native protocol", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "journal.socket")
+		listener, e := net.ListenPacket("unixgram", socket)
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+
+		appender := NewJournaldAppender(JournaldConfig{SocketPath: socket})
+		defer appender.Close()
+		appender.Print(entry)
+
+		buffer := make([]byte, 1024)
+		_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, e := listener.ReadFrom(buffer)
+		if e != nil {
+			t.Fatal("Failed to receive entry :", e)
+		}
+		expected := "MESSAGE=slow query\nPRIORITY=4\nSYSLOG_IDENTIFIER=DB\nCODE_FILE=/src/db.go\nCODE_LINE=12\n" +
+			"CODE_FUNC=example.com/db.(*Pool).Query\nQUERY_ID=7\nSQL\n\x12\x00\x00\x00\x00\x00\x00\x00SELECT 1\nFROM dual\n"
+		if received := string(buffer[:n]); received != expected {
+			t.Errorf("Unexpected serialized entry: %q", received)
+		}
+	})
+
+	t.Run("Test fallback without journal", func(t *testing.T) {
+		fallback := &bytes.Buffer{}
+		appender := NewJournaldAppender(JournaldConfig{SocketPath: filepath.Join(t.TempDir(), "missing"), Fallback: fallback})
+		appender.Print(entry)
+
+		if fallback.String() != "<4>slow query query-id=7 sql=\"SELECT 1\\nFROM dual\"\n" {
+			t.Errorf("Unexpected fallback output: %q", fallback.String())
+		}
+	})
+
+	t.Run("Test connecting to journal started later", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "journal.socket")
+		fallback := &bytes.Buffer{}
+		appender := NewJournaldAppender(JournaldConfig{SocketPath: socket, Fallback: fallback, RetryInterval: time.Millisecond})
+		defer appender.Close()
+		appender.Print(entry)
+		if fallback.Len() == 0 {
+			t.Error("Entry not written to fallback without journal")
+		}
+
+		listener, e := net.ListenPacket("unixgram", socket)
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+		time.Sleep(2 * time.Millisecond)
+		appender.Print(&LogEntry{Logger: "DB", Level: ERROR, Message: "reconnected"})
+
+		buffer := make([]byte, 1024)
+		_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, e := listener.ReadFrom(buffer)
+		if e != nil {
+			t.Fatal("Failed to receive entry after journal started :", e)
+		}
+		if received := string(buffer[:n]); received != "MESSAGE=reconnected\nPRIORITY=3\nSYSLOG_IDENTIFIER=DB\n" {
+			t.Errorf("Unexpected serialized entry: %q", received)
+		}
+	})
+
+	t.Run("Test reserved field names", func(t *testing.T) {
+		appender := &JournaldAppender{}
+		appender.serialize(&LogEntry{Logger: "DB", Level: INFO, Message: "entry", Fields: []Field{F("message", "field"), F("priority", 1)}})
+		if serialized := string(appender.buffer); serialized !=
+			"MESSAGE=entry\nPRIORITY=6\nSYSLOG_IDENTIFIER=DB\nFIELD_MESSAGE=field\nFIELD_PRIORITY=1\n" {
+			t.Errorf("Unexpected serialized entry: %q", serialized)
+		}
+	})
+
+	t.Run("Test journal field names", func(t *testing.T) {
+		for key, expected := range map[string]string{"user.id": "USER_ID", "_private": "PRIVATE", "1st": "FIELD_1ST", "": "FIELD_"} {
+			if name := journalFieldName(key); name != expected {
+				t.Errorf("Unexpected field name for %q: %v (expected %v)", key, name, expected)
+			}
+		}
+	})
+}
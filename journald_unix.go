@@ -0,0 +1,54 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+//go:build unix
+
+package log
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// oversizedDatagram returns true if the error reports a datagram too big to be sent
+func oversizedDatagram(e error) bool {
+	return errors.Is(e, syscall.EMSGSIZE) || errors.Is(e, syscall.ENOBUFS)
+}
+
+// sendJournalFile passes the serialized entry to the journal through the descriptor of an unlinked temporary file.
+// The journal only accepts such files from /dev/shm or the temporary directories.
+func sendJournalFile(connection net.Conn, data []byte) error {
+	rawConnection, ok := connection.(syscall.Conn)
+	if !ok {
+		return ErrUnsupportedJournalTransfer
+	}
+	raw, e := rawConnection.SyscallConn()
+	if e != nil {
+		return e
+	}
+	file, e := os.CreateTemp("/dev/shm", "journal-")
+	if e != nil {
+		if file, e = os.CreateTemp("", "journal-"); e != nil {
+			return e
+		}
+	}
+	defer file.Close()
+	if e = os.Remove(file.Name()); e != nil {
+		return e
+	}
+	if _, e = file.Write(data); e != nil {
+		return e
+	}
+	rights := syscall.UnixRights(int(file.Fd()))
+	var sendError error
+	if e = raw.Write(func(fd uintptr) bool {
+		sendError = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return sendError != syscall.EAGAIN
+	}); e != nil {
+		return e
+	}
+	return sendError
+}
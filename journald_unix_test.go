@@ -0,0 +1,62 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+//go:build unix
+
+package log
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestJournaldOversizedEntries(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.socket")
+	listener, e := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if e != nil {
+		t.Fatal("Unable to listen :", e)
+	}
+	defer listener.Close()
+
+	fallback := &bytes.Buffer{}
+	appender := NewJournaldAppender(JournaldConfig{SocketPath: socket, Fallback: fallback})
+	defer appender.Close()
+	message := strings.Repeat("x", 4<<20)
+	appender.Print(&LogEntry{Logger: "BIG", Level: INFO, Message: message})
+
+	if fallback.Len() > 0 {
+		t.Fatal("Oversized entry written to fallback")
+	}
+
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+	_, oobn, _, _, e := listener.ReadMsgUnix(make([]byte, 1), oob)
+	if e != nil {
+		t.Fatal("Failed to receive entry :", e)
+	}
+	messages, e := syscall.ParseSocketControlMessage(oob[:oobn])
+	if e != nil || len(messages) != 1 {
+		t.Fatal("File descriptor not received :", e)
+	}
+	fds, e := syscall.ParseUnixRights(&messages[0])
+	if e != nil || len(fds) != 1 {
+		t.Fatal("File descriptor not received :", e)
+	}
+	file := os.NewFile(uintptr(fds[0]), "journal")
+	defer file.Close()
+	data, e := io.ReadAll(io.NewSectionReader(file, 0, 1<<30))
+	if e != nil {
+		t.Fatal("Unable to read passed file :", e)
+	}
+	if expected := "MESSAGE=" + message + "\nPRIORITY=6\nSYSLOG_IDENTIFIER=BIG\n"; string(data) != expected {
+		t.Errorf("Unexpected serialized entry of %d bytes", len(data))
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// names of the logger types, as reported by logger descriptors
+var loggerTypeNames = []string{"Standard", "SyncedAppenders"}
+
+// LoggerDescriptor describes a registered logger, its current level and the options it was created with
+type LoggerDescriptor struct {
+	Name         string     // name of the logger
+	Level        int        // current level of the logger
+	Verbosity    int        // current verbosity of the logger
+	VModules     []VModule  // current verbosity of the source files matching patterns
+	Type         string     // type of the logger (Standard or SyncedAppenders)
+	DateFlags    int        // date flags of the header
+	LevelFormats [][]uint   // header format of each level
+	Writer       io.Writer  // writer of a standard logger
+	Appenders    []Appender // appenders of a synced appenders logger
+}
+
+// decorator is implemented by appenders decorating other appenders
+type decorator interface {
+	decorated() Appender
+}
+
+// flusher is implemented by appenders holding back entries
+type flusher interface {
+	flush()
+}
+
+// Remove removes the logger with the given name from the registry, without closing its writer or appenders. The
+// default logger can't be removed.
+func (r *Registry) Remove(name string) error {
+	if name == DEFAULT {
+		return ErrRemovingDefaultLogger
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.loggers[name]; !found {
+		return ErrLoggerDoesNotExist
+	}
+	delete(r.loggers, name)
+	return nil
+}
+
+// Loggers returns the descriptors of all loggers in the registry, sorted by name
+func (r *Registry) Loggers() []LoggerDescriptor {
+	r.lock.Lock()
+	descriptors := make([]LoggerDescriptor, 0, len(r.loggers))
+	for name, logger := range r.loggers {
+		o := logger.(managedLogger).loggerOptions()
+		descriptor := LoggerDescriptor{
+			Name:         name,
+			Level:        logger.Level(),
+			Verbosity:    logger.Verbosity(),
+			VModules:     logger.(managedLogger).vmodules(),
+			DateFlags:    o.dateFlags,
+			LevelFormats: make([][]uint, len(o.levelFormats)),
+			Writer:       o.writer,
+			Appenders:    append([]Appender(nil), o.appenders...),
+		}
+		if o.loggerType < uint(len(loggerTypeNames)) {
+			descriptor.Type = loggerTypeNames[o.loggerType]
+		}
+		for i, format := range o.levelFormats {
+			descriptor.LevelFormats[i] = append([]uint(nil), format...)
+		}
+		if o.loggerType == standard && o.writer == nil {
+			descriptor.Writer = os.Stdout
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	r.lock.Unlock()
+
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].Name < descriptors[j].Name
+	})
+	return descriptors
+}
+
+// Shutdown flushes the entries held back by the loggers of the registry and closes their writers, appenders and the
+// writers and appenders of their routes. Decorating appenders are flushed before the appenders they decorate are
+// closed, appenders shared by several loggers are closed once and the standard output and error are never closed.
+// Loggers should not be used after the shutdown. If the context is done before all are closed its error is returned,
+// otherwise the errors of the failed closes are.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.lock.Lock()
+	loggers := make([]Logger, 0, len(r.loggers))
+	for _, logger := range r.loggers {
+		loggers = append(loggers, logger)
+	}
+	r.lock.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- shutdown(loggers)
+	}()
+	select {
+	case e := <-done:
+		return e
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdown flushes the loggers and closes their outputs, appenders in dependency order (decorators first) and writers
+// last
+func shutdown(loggers []Logger) error {
+	var writers []io.Writer
+	var appenders []Appender
+	seen := make(map[interface{}]bool)
+	var visit func(appender Appender)
+	visit = func(appender Appender) {
+		if !markSeen(seen, appender) {
+			return
+		}
+		if d, isDecorator := appender.(decorator); isDecorator {
+			visit(d.decorated())
+		}
+		appenders = append(appenders, appender)
+	}
+	addWriter := func(writer io.Writer) {
+		if writer != nil && writer != os.Stdout && writer != os.Stderr && markSeen(seen, writer) {
+			writers = append(writers, writer)
+		}
+	}
+
+	for _, logger := range loggers {
+		managed := logger.(managedLogger)
+		managed.flush()
+		o := managed.loggerOptions()
+		addWriter(o.writer)
+		for _, appender := range o.appenders {
+			visit(appender)
+		}
+		for _, route := range o.routes {
+			addWriter(route.Writer)
+			for _, appender := range route.Appenders {
+				visit(appender)
+			}
+		}
+	}
+
+	// appenders were collected after the appenders they decorate, closing them in reverse order
+	var errs []error
+	for i := len(appenders) - 1; i >= 0; i-- {
+		if f, isFlusher := appenders[i].(flusher); isFlusher {
+			f.flush()
+		}
+		if closer, isCloser := appenders[i].(io.Closer); isCloser {
+			if e := closer.Close(); e != nil {
+				errs = append(errs, e)
+			}
+		}
+	}
+	for _, writer := range writers {
+		if closer, isCloser := writer.(io.Closer); isCloser {
+			if e := closer.Close(); e != nil {
+				errs = append(errs, e)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// markSeen marks the value as seen, returning false if it was already seen. Values of non-comparable types can't be
+// tracked and are always considered unseen.
+func markSeen(seen map[interface{}]bool, value interface{}) bool {
+	if !reflect.TypeOf(value).Comparable() {
+		return true
+	}
+	if seen[value] {
+		return false
+	}
+	seen[value] = true
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// closingAppender appender recording its entries and keeping track of the order it's closed in
+type closingAppender struct {
+	lockedAppender
+	name    string
+	closed  *[]string
+	blocker chan struct{}
+}
+
+func (ca *closingAppender) Close() error {
+	if ca.blocker != nil {
+		<-ca.blocker
+	}
+	*ca.closed = append(*ca.closed, ca.name)
+	return nil
+}
+
+// closingBuffer writer keeping track of being closed
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (cb *closingBuffer) Close() error {
+	cb.closed = true
+	return nil
+}
+
+func TestLoggerLifecycle(t *testing.T) {
+	t.Run("Test removing loggers", func(t *testing.T) {
+		registry := NewRegistry()
+		_, _ = registry.Get("Removed")
+		if e := registry.Remove("Removed"); e != nil {
+			t.Error("Removing an existing logger failed :", e)
+		}
+		if e := registry.Remove("Removed"); e != ErrLoggerDoesNotExist {
+			t.Error("Removing a missing logger should fail :", e)
+		}
+		if e := registry.Remove(DEFAULT); e != ErrRemovingDefaultLogger {
+			t.Error("Removing the default logger should fail :", e)
+		}
+	})
+
+	t.Run("Test listing loggers", func(t *testing.T) {
+		registry := NewRegistry()
+		appender := &lockedAppender{}
+		_, _ = registry.GetWithOptions("Appenders", SyncedAppenders().WithAppenders(appender).WithStartingLevel(DEBUG))
+		_, _ = registry.GetWithOptions("Writer", Standard().WithLogPrefix(Name, LogLevel))
+		descriptors := registry.Loggers()
+		if len(descriptors) != 3 || descriptors[0].Name != "Appenders" || descriptors[1].Name != DEFAULT || descriptors[2].Name != "Writer" {
+			t.Fatal("Unexpected logger descriptors :", descriptors)
+		}
+		if d := descriptors[0]; d.Type != "SyncedAppenders" || d.Level != DEBUG || len(d.Appenders) != 1 || d.Appenders[0] != appender {
+			t.Error("Unexpected synced appenders descriptor :", d)
+		}
+		if d := descriptors[2]; d.Type != "Standard" || len(d.LevelFormats[INFO]) != 2 || d.LevelFormats[INFO][0] != Name || d.Writer == nil {
+			t.Error("Unexpected standard descriptor :", d)
+		}
+	})
+
+	t.Run("Test shutdown", func(t *testing.T) {
+		registry := NewRegistry()
+		var closed []string
+		inner := &closingAppender{name: "inner", closed: &closed}
+		routed := &closingAppender{name: "routed", closed: &closed}
+		deduplicated := Deduplicate(inner, time.Hour)
+		writer := &closingBuffer{}
+		logger, _ := registry.GetWithOptions("Appenders", SyncedAppenders().WithAppenders(deduplicated, inner).
+			WithRoutes(Route{Match: LevelRange(CRITICAL, ERROR), Appenders: []Appender{routed}}))
+		_, _ = registry.GetWithOptions("Writer", Standard().WithWriter(writer))
+		logger.Warning("repeated")
+		logger.Warning("repeated")
+		logger.Error("routed")
+
+		if e := registry.Shutdown(context.Background()); e != nil {
+			t.Error("Shutdown failed :", e)
+		}
+		if len(closed) != 2 || closed[1] != "inner" {
+			t.Error("Appenders not closed once in dependency order :", closed)
+		}
+		if messages := inner.messages(); len(messages) != 4 || messages[3] != "repeated\n" {
+			t.Error("Repetitions not flushed before closing :", messages)
+		}
+		if !writer.closed {
+			t.Error("Writer not closed.")
+		}
+	})
+
+	t.Run("Test shutdown deadline", func(t *testing.T) {
+		registry := NewRegistry()
+		var closed []string
+		blocked := &closingAppender{name: "blocked", closed: &closed, blocker: make(chan struct{})}
+		defer close(blocked.blocker)
+		_, _ = registry.GetWithOptions("Blocked", SyncedAppenders().WithAppenders(blocked))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if e := registry.Shutdown(ctx); e != context.DeadlineExceeded {
+			t.Error("Shutdown should fail when the context is done :", e)
+		}
+	})
+}
@@ -5,7 +5,10 @@
 package log
 
 import (
-	"sync"
+	"context"
+	"time"
+
+	"github.com/gomatbase/go-log/internal/hooks"
 )
 
 // Log Severity levels
@@ -38,68 +41,76 @@ var (
 	}
 )
 
-var (
-	loggers          = make(map[string]Logger)             // map of all existing loggers. Indexed by their names.
-	lock             = sync.Mutex{}                        // mutex to manipulate the loggers map
-	defaultLogger, _ = getWithOptions(DEFAULT, Standard()) // the default logger provided by the package for out-of-the-box usage with default options.
-)
+// the registry used by the package functions. Its default logger is called through the package functions, one frame
+// deeper than when called directly.
+var defaultRegistry = newRegistry(4)
 
-// Get will create or get an existing logger with the given name. If the logger doesn't exist it will be created with
-// the default options (warning level, logs to stdout and non-failing criticals). The name must be a non-empty string
-// (may be spaces).
-func Get(name string) (Logger, error) {
-	logger, e := GetWithOptions(name, Standard())
-	var returnError error
-	if e != nil && e != ErrReinitializingExistingLogger {
-		returnError = e
+func init() {
+	hooks.SetDefaultLogger = func(logger interface{}) interface{} {
+		return defaultRegistry.setDefaultLogger(logger.(Logger))
 	}
-	return logger, returnError
 }
 
-// GetWithOptions will create a log with the provided options if it doesn't exist yet or returns an existing log if
-// the provided options are the same as the options the existing logger was created with. Trying to get an existing
-// logger with different options. The name logger may not be an empty string (can be filled spaces).
+// Get will create or get an existing logger with the given name in the default registry. If the logger doesn't exist
+// it will be created with the default options (warning level, logs to stdout and non-failing criticals). The name must
+// be a non-empty string (may be spaces).
+func Get(name string) (Logger, error) {
+	return defaultRegistry.Get(name)
+}
+
+// GetWithOptions will create a log with the provided options in the default registry if it doesn't exist yet or
+// returns an existing log if the provided options are the same as the options the existing logger was created with.
+// The name logger may not be an empty string (can be filled spaces).
 func GetWithOptions(name string, options Options) (Logger, error) {
+	return defaultRegistry.GetWithOptions(name, options)
+}
+
+// GetOrReconfigure will create a log with the provided options in the default registry if it doesn't exist yet or
+// returns the existing log after applying the options to it in place
+func GetOrReconfigure(name string, options Options) (Logger, error) {
+	return defaultRegistry.GetOrReconfigure(name, options)
+}
+
+// Reconfigure applies the options to the existing logger with the given name in the default registry, in place
+func Reconfigure(name string, options Options) error {
+	return defaultRegistry.Reconfigure(name, options)
+}
+
+// New creates a logger with the given name and options which is not registered, so it can't be fetched through Get
+// and it's not affected by the package level settings. Meant for loggers with a limited scope, like in tests.
+func New(name string, o Options) (Logger, error) {
 	if len(name) == 0 {
 		return nil, ErrEmptyLoggerName
 	}
-	return getWithOptions(name, options)
-}
-
-// private getWithOptions function that actually fetches or creates the logger. The internal version allows an empty
-// string as a name allowing the creation of the DEFAULT logger.
-func getWithOptions(name string, o Options) (Logger, error) {
-	lock.Lock()
-	defer lock.Unlock()
-
-	if logger, found := loggers[name]; !found {
-		if o == nil {
-			o = Standard()
-		}
-		if logger, e := newLogger(name, o.(*options)); e != nil {
-			return nil, e
-		} else {
-			loggers[name] = logger
-			return logger, nil
-		}
-	} else {
-		return logger, nil
+	if o == nil {
+		o = Standard()
 	}
+	return newLogger(name, o.(*options))
 }
 
 // SetDefaultLogger allows overriding the default logger with different options
 func SetDefaultLogger(o Options) error {
-	lock.Lock()
-	defer lock.Unlock()
-
-	if logger, e := newLogger(DEFAULT, o.(*options)); e != nil {
-		return e
-	} else {
-		defaultLogger = logger
-		loggers[DEFAULT] = logger
-	}
+	return defaultRegistry.SetDefaultLogger(o)
+}
+
+// DefaultLoggerOptions returns the options the current default logger was created with
+func DefaultLoggerOptions() Options {
+	return defaultRegistry.DefaultLoggerOptions()
+}
+
+// Remove removes the logger with the given name from the default registry, without closing its writer or appenders
+func Remove(name string) error {
+	return defaultRegistry.Remove(name)
+}
 
-	return nil
+// Loggers returns the descriptors of all loggers in the default registry, sorted by name
+func Loggers() []LoggerDescriptor {
+	return defaultRegistry.Loggers()
+}
+
+// Shutdown flushes the loggers of the default registry and closes their writers and appenders
+func Shutdown(ctx context.Context) error {
+	return defaultRegistry.Shutdown(ctx)
 }
 
 // newLogger creates a new logger with the given name from the provided options
@@ -108,7 +119,7 @@ func newLogger(name string, o *options) (Logger, error) {
 	case standard:
 		return newStandardLogger(name, o), nil
 	case syncedAppender:
-		return newStandardLogger(name, o), nil
+		return newSyncedAppenders(name, o), nil
 	default:
 		return nil, ErrUnknownLoggerType
 	}
@@ -116,84 +127,101 @@ func newLogger(name string, o *options) (Logger, error) {
 
 // SetLevel sets the log level of the default logger
 func SetLevel(level int) {
-	defaultLogger.SetLevel(level)
+	defaultRegistry.SetLevel(level)
 }
 
 // SetLoggerLevel sets the log level of a logger by name. DEFAULT may be used to set the default logger level.
 func SetLoggerLevel(name string, level int) error {
-	lock.Lock()
-	logger, found := loggers[name]
-	lock.Unlock()
-
-	if !found {
-		return ErrLoggerDoesNotExist
-	}
-
-	logger.SetLevel(level)
-	return nil
+	return defaultRegistry.SetLoggerLevel(name, level)
 }
 
 // SetLoggerLevels sets the log levels of several loggers at once. If any logger is not found it will be omitted from the response
 func SetLoggerLevels(loggerLevels map[string]int) map[string]int {
-	result := make(map[string]int)
+	return defaultRegistry.SetLoggerLevels(loggerLevels)
+}
 
-	for k, l := range loggerLevels {
-		if e := SetLoggerLevel(k, l); e == nil {
-			result[k] = l
-		}
-	}
+// Enabled returns true if entries at the given level are logged by the default logger
+func Enabled(level int) bool {
+	return defaultRegistry.Default().Enabled(level)
+}
 
-	return result
+// EnabledCtx returns true if entries at the given level are logged by the default logger when logged with the context
+func EnabledCtx(ctx context.Context, level int) bool {
+	return defaultRegistry.Default().EnabledCtx(ctx, level)
+}
+
+// PushLevel temporarily sets the level of the logger with the given name in the default registry until the returned
+// function is called, restoring its previous level (defer log.PushLevel("db", TRACE)()). The returned function does
+// nothing if the logger doesn't exist (see PushLevelE).
+func PushLevel(name string, level int) func() {
+	restore, _ := defaultRegistry.PushLevel(name, level)
+	return restore
+}
+
+// PushLevelE is the same as PushLevel, returning ErrLoggerDoesNotExist if the logger doesn't exist
+func PushLevelE(name string, level int) (func(), error) {
+	return defaultRegistry.PushLevel(name, level)
+}
+
+// OverrideLevel temporarily sets the level of the logger with the given name in the default registry for the given
+// duration, or until the returned function is called. The returned function does nothing if the logger doesn't exist
+// (see OverrideLevelE).
+func OverrideLevel(name string, level int, duration time.Duration) func() {
+	restore, _ := defaultRegistry.OverrideLevel(name, level, duration)
+	return restore
+}
+
+// OverrideLevelE is the same as OverrideLevel, returning ErrLoggerDoesNotExist if the logger doesn't exist
+func OverrideLevelE(name string, level int, duration time.Duration) (func(), error) {
+	return defaultRegistry.OverrideLevel(name, level, duration)
 }
 
 // Level returns the current log level of the default logger
 func Level() int {
-	return defaultLogger.Level()
+	return defaultRegistry.Level()
 }
 
-// LoggerLevels gets the current log levels of all known loggers
+// LoggerLevels gets the current log levels of all known loggers (see LoggerVerbosities for their verbosity)
 func LoggerLevels() map[string]int {
-	loggerLevels := make(map[string]int)
-	lock.Lock()
-	for k, l := range loggers {
-		loggerLevels[k] = l.Level()
-	}
-	lock.Unlock()
-	return loggerLevels
+	return defaultRegistry.LoggerLevels()
+}
+
+// SetLoggerVerbosity sets the verbosity of a logger by name. DEFAULT may be used to set the default logger verbosity.
+func SetLoggerVerbosity(name string, verbosity int) error {
+	return defaultRegistry.SetLoggerVerbosity(name, verbosity)
+}
+
+// SetLoggerVModule sets the verbosity of the source files matching patterns of a logger by name
+func SetLoggerVModule(name string, spec string) error {
+	return defaultRegistry.SetLoggerVModule(name, spec)
+}
+
+// LoggerVerbosities gets the current verbosity of all known loggers
+func LoggerVerbosities() map[string]int {
+	return defaultRegistry.LoggerVerbosities()
+}
+
+// V returns the handle logging entries of the given verbosity level through the default logger
+func V(level int) Verbose {
+	logger := defaultRegistry.Default()
+	return Verbose{logger: logger, level: level, enabled: logger.(managedLogger).verbose(level, 1)}
 }
 
 // LoggerLevel gets the current log level of the logger with the given name. ErrLoggerDoesNotExist is returned as an
 // error if a logger with the given name doesn't is unknown.
 func LoggerLevel(name string) (int, error) {
-	lock.Lock()
-	logger, found := loggers[name]
-	lock.Unlock()
-
-	if !found {
-		return 0, ErrLoggerDoesNotExist
-	}
-
-	return logger.Level(), nil
+	return defaultRegistry.LoggerLevel(name)
 }
 
 // LoggerLevelName gets the current log level name of the logger with the given name. ErrLoggerDoesNotExist is returned as an
 // error if a logger with the given name doesn't is unknown. Utility method for loggers using the standard severity scale.
 func LoggerLevelName(name string) (string, error) {
-	if level, e := LoggerLevel(name); e == nil {
-		return LevelName(level), nil
-	} else {
-		return "", e
-	}
+	return defaultRegistry.LoggerLevelName(name)
 }
 
 // LoggerLevelNames gets the current log level names of all known loggers. Only relevant if logger is using the standard severity scale.
 func LoggerLevelNames() map[string]string {
-	loggerLevels := LoggerLevels()
-	loggerLevelNames := make(map[string]string)
-	for k, l := range loggerLevels {
-		loggerLevelNames[k] = LevelName(l)
-	}
-	return loggerLevelNames
+	return defaultRegistry.LoggerLevelNames()
 }
 
 // LevelName is a convenience method to translate the log level into a name. It only works for loggers implementing
@@ -214,60 +242,120 @@ func LevelSeverity(name string) int {
 
 // Critical logs a critical log entry through the default logger
 func Critical(v ...interface{}) {
-	defaultLogger.Critical(v...)
+	defaultRegistry.Default().Critical(v...)
 }
 
 // Criticalf logs a formatted critical log entry through the default logger
 func Criticalf(format string, v ...interface{}) {
-	defaultLogger.Criticalf(format, v...)
+	defaultRegistry.Default().Criticalf(format, v...)
 }
 
 // Error logs a error log entry through the default logger
 func Error(v ...interface{}) {
-	defaultLogger.Error(v...)
+	defaultRegistry.Default().Error(v...)
 }
 
 // Errorf logs a formatted error log entry through the default logger
 func Errorf(format string, v ...interface{}) {
-	defaultLogger.Errorf(format, v...)
+	defaultRegistry.Default().Errorf(format, v...)
 }
 
 // Warning logs a warning log entry through the default logger
 func Warning(v ...interface{}) {
-	defaultLogger.Warning(v...)
+	defaultRegistry.Default().Warning(v...)
 }
 
 // Warningf logs a formatted warning log entry through the default logger
 func Warningf(format string, v ...interface{}) {
-	defaultLogger.Warningf(format, v...)
+	defaultRegistry.Default().Warningf(format, v...)
 }
 
 // Info logs a info log entry through the default logger
 func Info(v ...interface{}) {
-	defaultLogger.Info(v...)
+	defaultRegistry.Default().Info(v...)
 }
 
 // Infof logs a formatted info log entry through the default logger
 func Infof(format string, v ...interface{}) {
-	defaultLogger.Infof(format, v...)
+	defaultRegistry.Default().Infof(format, v...)
 }
 
 // Debug logs a debug log entry through the default logger
 func Debug(v ...interface{}) {
-	defaultLogger.Debug(v...)
+	defaultRegistry.Default().Debug(v...)
 }
 
 // Debugf logs a formatted debug log entry through the default logger
 func Debugf(format string, v ...interface{}) {
-	defaultLogger.Debugf(format, v...)
+	defaultRegistry.Default().Debugf(format, v...)
 }
 
 // Trace logs a trace log entry through the default logger
 func Trace(v ...interface{}) {
-	defaultLogger.Trace(v...)
+	defaultRegistry.Default().Trace(v...)
 }
 
 // Tracef logs a formatted trace log entry through the default logger
 func Tracef(format string, v ...interface{}) {
-	defaultLogger.Tracef(format, v...)
+	defaultRegistry.Default().Tracef(format, v...)
+}
+
+// CriticalCtx logs a critical log entry through the default logger with the fields extracted from the context
+func CriticalCtx(ctx context.Context, v ...interface{}) {
+	defaultRegistry.Default().CriticalCtx(ctx, v...)
+}
+
+// CriticalfCtx logs a formatted critical log entry through the default logger with the fields extracted from the context
+func CriticalfCtx(ctx context.Context, format string, v ...interface{}) {
+	defaultRegistry.Default().CriticalfCtx(ctx, format, v...)
+}
+
+// ErrorCtx logs an error log entry through the default logger with the fields extracted from the context
+func ErrorCtx(ctx context.Context, v ...interface{}) {
+	defaultRegistry.Default().ErrorCtx(ctx, v...)
+}
+
+// ErrorfCtx logs a formatted error log entry through the default logger with the fields extracted from the context
+func ErrorfCtx(ctx context.Context, format string, v ...interface{}) {
+	defaultRegistry.Default().ErrorfCtx(ctx, format, v...)
+}
+
+// WarningCtx logs a warning log entry through the default logger with the fields extracted from the context
+func WarningCtx(ctx context.Context, v ...interface{}) {
+	defaultRegistry.Default().WarningCtx(ctx, v...)
+}
+
+// WarningfCtx logs a formatted warning log entry through the default logger with the fields extracted from the context
+func WarningfCtx(ctx context.Context, format string, v ...interface{}) {
+	defaultRegistry.Default().WarningfCtx(ctx, format, v...)
+}
+
+// InfoCtx logs an info log entry through the default logger with the fields extracted from the context
+func InfoCtx(ctx context.Context, v ...interface{}) {
+	defaultRegistry.Default().InfoCtx(ctx, v...)
+}
+
+// InfofCtx logs a formatted info log entry through the default logger with the fields extracted from the context
+func InfofCtx(ctx context.Context, format string, v ...interface{}) {
+	defaultRegistry.Default().InfofCtx(ctx, format, v...)
+}
+
+// DebugCtx logs a debug log entry through the default logger with the fields extracted from the context
+func DebugCtx(ctx context.Context, v ...interface{}) {
+	defaultRegistry.Default().DebugCtx(ctx, v...)
+}
+
+// DebugfCtx logs a formatted debug log entry through the default logger with the fields extracted from the context
+func DebugfCtx(ctx context.Context, format string, v ...interface{}) {
+	defaultRegistry.Default().DebugfCtx(ctx, format, v...)
+}
+
+// TraceCtx logs a trace log entry through the default logger with the fields extracted from the context
+func TraceCtx(ctx context.Context, v ...interface{}) {
+	defaultRegistry.Default().TraceCtx(ctx, v...)
+}
+
+// TracefCtx logs a formatted trace log entry through the default logger with the fields extracted from the context
+func TracefCtx(ctx context.Context, format string, v ...interface{}) {
+	defaultRegistry.Default().TracefCtx(ctx, format, v...)
 }
@@ -6,6 +6,7 @@ package log
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 )
 
@@ -13,9 +14,8 @@ var buf = &bytes.Buffer{}
 
 func resetLoggers() {
 	buf.Reset()
-	loggers = make(map[string]Logger)
-	defaultLogger, _ = newLogger(DEFAULT, Standard().WithWriter(buf).(*options))
-	loggers[DEFAULT] = defaultLogger
+	defaultRegistry = newRegistry(4)
+	_ = defaultRegistry.SetDefaultLogger(Standard().WithWriter(buf))
 }
 
 func TestGettingLog(t *testing.T) {
@@ -43,12 +43,28 @@ func TestGettingLog(t *testing.T) {
 
 	t.Run("Test getting existing log with new options", func(t *testing.T) {
 		overrideLog, e := GetWithOptions("Test Override", Standard().WithFailingCriticals())
-		if e != nil {
-			t.Error("Getting log with new options should not fail.")
+		if !errors.Is(e, ErrReinitializingExistingLogger) || e.Error() != "trying to initialize an already initialized logger with different options: different failing criticals" {
+			t.Error("Getting log with new options should fail reporting the differing option :", e)
 		}
 		if overrideLog != log {
 			t.Error("Getting log with new options failing but returning a new log.")
 		}
+		if _, e = Get("Test Override"); e != nil {
+			t.Error("Getting existing log without options should not fail :", e)
+		}
+	})
+
+	t.Run("Test reconfiguring existing log", func(t *testing.T) {
+		reconfiguredLog, e := GetOrReconfigure("Test Override", Standard().WithStartingLevel(DEBUG))
+		if e != nil {
+			t.Error("Reconfiguring existing log should not fail :", e)
+		}
+		if reconfiguredLog != log || log.Level() != DEBUG {
+			t.Error("Reconfiguring existing log not applied in place.")
+		}
+		if _, e = GetOrReconfigure("Test Override", SyncedAppenders()); !errors.Is(e, ErrReinitializingExistingLogger) {
+			t.Error("Reconfiguring existing log with another logger type should fail :", e)
+		}
 	})
 
 	t.Run("Test getting logger with null options", func(t *testing.T) {
@@ -0,0 +1,135 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+// Package logtest provides helpers to record log entries in tests and assert on them
+package logtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gomatbase/go-log"
+	"github.com/gomatbase/go-log/internal/hooks"
+)
+
+// Recorder appender recording the entries it's handed, providing assertions on them
+type Recorder struct {
+	entries []*log.LogEntry
+	mutex   sync.Mutex
+}
+
+// NewRecorder creates a recorder, cleared when the test finishes
+func NewRecorder(t testing.TB) *Recorder {
+	recorder := &Recorder{}
+	t.Cleanup(recorder.Reset)
+	return recorder
+}
+
+// NewLogger creates a logger recording all its entries (TRACE level) in the returned recorder. The logger is not
+// registered, so it doesn't interfere with other loggers with the same name.
+func NewLogger(t testing.TB, name string) (log.Logger, *Recorder) {
+	t.Helper()
+	recorder := NewRecorder(t)
+	logger, e := log.New(name, log.SyncedAppenders().WithAppenders(recorder).WithStartingLevel(log.TRACE))
+	if e != nil {
+		t.Fatal("Unable to create recording logger :", e)
+	}
+	return logger, recorder
+}
+
+// SwapDefault replaces the default logger, through SetDefaultLogger, with a logger recording all its entries (TRACE
+// level) in the returned recorder. The previous default logger is put back when the test finishes. Tests swapping the
+// default logger must not run in parallel.
+func SwapDefault(t testing.TB) *Recorder {
+	t.Helper()
+	previous, e := log.Get(log.DEFAULT)
+	if e != nil {
+		t.Fatal("Unable to get default logger :", e)
+	}
+	recorder := NewRecorder(t)
+	if e = log.SetDefaultLogger(log.SyncedAppenders().WithAppenders(recorder).WithStartingLevel(log.TRACE)); e != nil {
+		t.Fatal("Unable to swap default logger :", e)
+	}
+	t.Cleanup(func() {
+		hooks.SetDefaultLogger(previous)
+	})
+	return recorder
+}
+
+// Print records the entry
+func (r *Recorder) Print(entry *log.LogEntry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+// Entries returns the recorded entries
+func (r *Recorder) Entries() []*log.LogEntry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*log.LogEntry(nil), r.entries...)
+}
+
+// Reset clears the recorded entries
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = nil
+}
+
+// Logged returns true if an entry was recorded at the level with a message containing the text
+func (r *Recorder) Logged(level int, text string) bool {
+	for _, entry := range r.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertLogged fails the test if no entry was recorded at the level with a message containing the text
+func (r *Recorder) AssertLogged(t testing.TB, level int, text string) bool {
+	t.Helper()
+	if r.Logged(level, text) {
+		return true
+	}
+	t.Errorf("No %v entry containing %q was logged. Entries:\n%s", log.LevelName(level), text, r.dump())
+	return false
+}
+
+// AssertNotLogged fails the test if an entry was recorded at the level with a message containing the text
+func (r *Recorder) AssertNotLogged(t testing.TB, level int, text string) bool {
+	t.Helper()
+	if !r.Logged(level, text) {
+		return true
+	}
+	t.Errorf("Unexpected %v entry containing %q was logged. Entries:\n%s", log.LevelName(level), text, r.dump())
+	return false
+}
+
+// AssertNoErrors fails the test if any ERROR or CRITICAL entry was recorded
+func (r *Recorder) AssertNoErrors(t testing.TB) bool {
+	t.Helper()
+	for _, entry := range r.Entries() {
+		if entry.Level <= log.ERROR {
+			t.Errorf("Unexpected error entries were logged. Entries:\n%s", r.dump())
+			return false
+		}
+	}
+	return true
+}
+
+// dump lists the recorded entries for failure messages
+func (r *Recorder) dump() string {
+	builder := &strings.Builder{}
+	for _, entry := range r.Entries() {
+		builder.WriteString("\t")
+		builder.WriteString(log.LevelName(entry.Level))
+		builder.WriteString(" ")
+		builder.WriteString(strings.TrimSuffix(entry.Message, "\n"))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
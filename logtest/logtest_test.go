@@ -0,0 +1,63 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package logtest
+
+import (
+	"testing"
+
+	"github.com/gomatbase/go-log"
+)
+
+// failureRecorder records failures of assertions expected to fail
+type failureRecorder struct {
+	testing.TB
+	failures int
+}
+
+func (fr *failureRecorder) Helper() {}
+
+func (fr *failureRecorder) Errorf(string, ...interface{}) {
+	fr.failures++
+}
+
+func TestRecordingLogger(t *testing.T) {
+	logger, recorder := NewLogger(t, "RECORDED")
+	logger.Info("user created")
+	logger.Debugf("%d users", 3)
+
+	if entries := recorder.Entries(); len(entries) != 2 || entries[0].Logger != "RECORDED" {
+		t.Error("Unexpected recorded entries :", entries)
+	}
+	recorder.AssertLogged(t, log.INFO, "created")
+	recorder.AssertNotLogged(t, log.INFO, "users")
+	recorder.AssertNoErrors(t)
+
+	failures := &failureRecorder{TB: t}
+	logger.Error("failed")
+	recorder.AssertLogged(failures, log.WARNING, "created")
+	recorder.AssertNoErrors(failures)
+	if failures.failures != 2 {
+		t.Error("Failing assertions not reported :", failures.failures)
+	}
+}
+
+func TestSwapDefault(t *testing.T) {
+	log.SetLevel(log.ERROR)
+	original, _ := log.Get(log.DEFAULT)
+	t.Run("Test recording default logger", func(t *testing.T) {
+		recorder := SwapDefault(t)
+		log.Trace("tracing")
+		recorder.AssertLogged(t, log.TRACE, "tracing")
+		if entries := recorder.Entries(); len(entries) != 1 || entries[0].Function != "TestSwapDefault.func1" {
+			t.Error("Unexpected default logger entries :", entries)
+		}
+	})
+	if log.Level() != log.ERROR {
+		t.Error("Default logger level not restored :", log.Level())
+	}
+	if restored, _ := log.Get(log.DEFAULT); restored != original {
+		t.Error("Original default logger instance not restored")
+	}
+}
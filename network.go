@@ -0,0 +1,237 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default network appender settings
+const (
+	DefaultNetworkBufferSize = 1000
+	DefaultMinBackoff        = 100 * time.Millisecond
+	DefaultMaxBackoff        = 30 * time.Second
+	DefaultDialTimeout       = 5 * time.Second
+	DefaultWriteTimeout      = 10 * time.Second
+)
+
+// DroppedKey is the key of the field holding the number of dropped entries in a dropped entries notice
+const DroppedKey = "dropped"
+
+// NetworkConfig holds the configuration of a network appender
+type NetworkConfig struct {
+	Network      string        // network of the collector: tcp, udp, unix or unixgram
+	Address      string        // address of the collector (host:port or socket path)
+	TLS          *tls.Config   // TLS configuration, tcp connections use TLS when set
+	Encoder      Encoder       // encoder of the entries (defaults to JSONEncoder)
+	BufferSize   int           // maximum number of entries kept while disconnected (defaults to DefaultNetworkBufferSize)
+	MinBackoff   time.Duration // initial delay between reconnection attempts (defaults to DefaultMinBackoff)
+	MaxBackoff   time.Duration // maximum delay between reconnection attempts (defaults to DefaultMaxBackoff)
+	DialTimeout  time.Duration // timeout to establish a connection (defaults to DefaultDialTimeout)
+	WriteTimeout time.Duration // timeout to send an entry, the connection is considered lost if exceeded (defaults to DefaultWriteTimeout)
+}
+
+// NetworkAppender appender shipping encoded entries to a remote collector. Entries are sent by a background
+// goroutine, which reconnects with exponential backoff when the connection is lost. While disconnected, entries are
+// kept in a bounded buffer, dropping the oldest ones when full. Dropped entries are counted and reported to the
+// collector with a notice entry once the connection is re-established.
+type NetworkAppender struct {
+	config     NetworkConfig
+	connection net.Conn
+	queue      [][]byte      // encoded entries waiting to be sent
+	lost       int           // entries dropped since the last notice sent to the collector
+	dropped    uint64        // total number of dropped entries
+	notify     chan struct{} // signals the sender that entries are waiting
+	done       chan struct{} // closed when the appender is closed
+	stopped    chan struct{} // closed when the sender finishes
+	closed     bool
+	mutex      sync.Mutex
+}
+
+// NewNetworkAppender creates a network appender. Connections are established in the background, so creating the
+// appender doesn't fail if the collector is not available.
+func NewNetworkAppender(config NetworkConfig) *NetworkAppender {
+	if config.Encoder == nil {
+		config.Encoder = JSONEncoder{}
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultNetworkBufferSize
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = DefaultMinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = DefaultDialTimeout
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = DefaultWriteTimeout
+	}
+	appender := &NetworkAppender{
+		config:  config,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go appender.send()
+	return appender
+}
+
+// Print encodes the entry and queues it to be sent
+func (na *NetworkAppender) Print(entry *LogEntry) {
+	encoded := na.config.Encoder.Encode(nil, entry)
+
+	na.mutex.Lock()
+	defer na.mutex.Unlock()
+	if na.closed {
+		return
+	}
+	if len(na.queue) >= na.config.BufferSize {
+		na.queue = na.queue[1:]
+		na.lost++
+		atomic.AddUint64(&na.dropped, 1)
+	}
+	na.queue = append(na.queue, encoded)
+	select {
+	case na.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Dropped returns the total number of entries dropped because the buffer was full
+func (na *NetworkAppender) Dropped() uint64 {
+	return atomic.LoadUint64(&na.dropped)
+}
+
+// Close stops the appender after trying to send the queued entries if connected, and closes the connection
+func (na *NetworkAppender) Close() error {
+	na.mutex.Lock()
+	if na.closed {
+		na.mutex.Unlock()
+		return nil
+	}
+	na.closed = true
+	close(na.done)
+	na.mutex.Unlock()
+
+	<-na.stopped
+	if na.connection != nil {
+		return na.connection.Close()
+	}
+	return nil
+}
+
+// dial establishes a connection to the collector
+func (na *NetworkAppender) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: na.config.DialTimeout}
+	if na.config.TLS != nil && (na.config.Network == "tcp" || na.config.Network == "tcp4" || na.config.Network == "tcp6") {
+		return tls.DialWithDialer(dialer, na.config.Network, na.config.Address, na.config.TLS)
+	}
+	return dialer.Dial(na.config.Network, na.config.Address)
+}
+
+// next takes the next entry to send, preceded by a notice of dropped entries if any were dropped since the last one
+func (na *NetworkAppender) next() []byte {
+	na.mutex.Lock()
+	defer na.mutex.Unlock()
+	if na.lost > 0 {
+		notice := &LogEntry{
+			Timestamp: time.Now(),
+			Level:     WARNING,
+			Message:   fmt.Sprintf("dropped %d entries", na.lost),
+			Fields:    []Field{F(DroppedKey, na.lost)},
+		}
+		na.lost = 0
+		return na.config.Encoder.Encode(nil, notice)
+	}
+	if len(na.queue) == 0 {
+		return nil
+	}
+	encoded := na.queue[0]
+	na.queue = na.queue[1:]
+	return encoded
+}
+
+// requeue puts back an entry which failed to be sent at the head of the queue
+func (na *NetworkAppender) requeue(encoded []byte) {
+	na.mutex.Lock()
+	defer na.mutex.Unlock()
+	if len(na.queue) >= na.config.BufferSize {
+		na.lost++
+		atomic.AddUint64(&na.dropped, 1)
+		return
+	}
+	na.queue = append([][]byte{encoded}, na.queue...)
+}
+
+// send is the sender loop, connecting to the collector and sending the queued entries until the appender is closed.
+// The backoff is only reset once the queued entries are sent, so collectors dropping connections right after accepting
+// them are not reconnected to in a tight loop.
+func (na *NetworkAppender) send() {
+	defer close(na.stopped)
+	backoff := na.config.MinBackoff
+	for {
+		if na.connection == nil {
+			connection, e := na.dial()
+			if e != nil {
+				if !na.wait(&backoff) {
+					return
+				}
+				continue
+			}
+			na.connection = connection
+		}
+
+		if !na.flush() {
+			if !na.wait(&backoff) {
+				return
+			}
+			continue
+		}
+		backoff = na.config.MinBackoff
+
+		select {
+		case <-na.done:
+			na.flush()
+			return
+		case <-na.notify:
+		}
+	}
+}
+
+// wait waits for the backoff before reconnecting, doubling it up to the maximum backoff. Returns false if the
+// appender was closed while waiting.
+func (na *NetworkAppender) wait(backoff *time.Duration) bool {
+	select {
+	case <-na.done:
+		return false
+	case <-time.After(*backoff):
+	}
+	if *backoff *= 2; *backoff > na.config.MaxBackoff {
+		*backoff = na.config.MaxBackoff
+	}
+	return true
+}
+
+// flush sends the queued entries, returning false if the connection was lost
+func (na *NetworkAppender) flush() bool {
+	for encoded := na.next(); encoded != nil; encoded = na.next() {
+		_ = na.connection.SetWriteDeadline(time.Now().Add(na.config.WriteTimeout))
+		if _, e := na.connection.Write(encoded); e != nil {
+			na.requeue(encoded)
+			_ = na.connection.Close()
+			na.connection = nil
+			return false
+		}
+	}
+	return true
+}
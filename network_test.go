@@ -0,0 +1,178 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// readJSONEntries reads count JSON entries from the first connection accepted by the listener
+func readJSONEntries(t *testing.T, listener net.Listener, count int) []map[string]interface{} {
+	connection, e := listener.Accept()
+	if e != nil {
+		t.Fatal("Failed to accept connection :", e)
+	}
+	defer connection.Close()
+	_ = connection.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(connection)
+	for len(entries) < count && scanner.Scan() {
+		entry := make(map[string]interface{})
+		if e := json.Unmarshal(scanner.Bytes(), &entry); e != nil {
+			t.Fatalf("Invalid JSON entry %q: %v", scanner.Text(), e)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestNetworkAppender(t *testing.T) {
+	t.Run("Test shipping entries", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+
+		appender := NewNetworkAppender(NetworkConfig{Network: "tcp", Address: listener.Addr().String()})
+		defer appender.Close()
+		logger, _ := newLogger("NETWORK", SyncedAppenders().WithAppenders(appender).(*options))
+		logger.Error("ERR", F("attempt", 2))
+		logger.Warning("WRN")
+
+		entries := readJSONEntries(t, listener, 2)
+		if len(entries) != 2 {
+			t.Fatal("Unexpected number of entries :", entries)
+		}
+		if entries[0]["message"] != "ERR" || entries[0]["level"] != "ERROR" || entries[0]["logger"] != "NETWORK" ||
+			entries[0]["fields"].(map[string]interface{})["attempt"] != 2.0 {
+			t.Error("Unexpected entry :", entries[0])
+		}
+		if entries[1]["message"] != "WRN" || entries[1]["function"] != "TestNetworkAppender.func1" {
+			t.Error("Unexpected entry :", entries[1])
+		}
+	})
+
+	t.Run("Test buffering while disconnected", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "collector.socket")
+		appender := NewNetworkAppender(NetworkConfig{
+			Network:    "unix",
+			Address:    socket,
+			Encoder:    TextEncoder{Prefix: []uint{LogLevel}},
+			BufferSize: 2,
+			MinBackoff: 5 * time.Millisecond,
+			MaxBackoff: 10 * time.Millisecond,
+		})
+		defer appender.Close()
+		for _, message := range []string{"first", "second", "third"} {
+			appender.Print(&LogEntry{Level: INFO, Message: message})
+		}
+		if appender.Dropped() != 1 {
+			t.Error("Unexpected number of dropped entries :", appender.Dropped())
+		}
+
+		listener, e := net.Listen("unix", socket)
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+		connection, e := listener.Accept()
+		if e != nil {
+			t.Fatal("Failed to accept connection :", e)
+		}
+		defer connection.Close()
+		_ = connection.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+		scanner := bufio.NewScanner(connection)
+		var lines []string
+		for len(lines) < 3 && scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) != 3 || lines[0] != "[WRN] dropped 1 entries dropped=1" || lines[1] != "[INF] second" || lines[2] != "[INF] third" {
+			t.Errorf("Unexpected lines after reconnecting: %q", lines)
+		}
+	})
+	t.Run("Test stalled collector", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+		go func() {
+			for {
+				connection, e := listener.Accept()
+				if e != nil {
+					return
+				}
+				defer connection.Close()
+			}
+		}()
+
+		appender := NewNetworkAppender(NetworkConfig{
+			Network:      "tcp",
+			Address:      listener.Addr().String(),
+			Encoder:      TextEncoder{},
+			WriteTimeout: 20 * time.Millisecond,
+		})
+		message := strings.Repeat("x", 1<<20)
+		for i := 0; i < 20; i++ {
+			appender.Print(&LogEntry{Level: INFO, Message: message})
+		}
+
+		closed := make(chan struct{})
+		go func() {
+			_ = appender.Close()
+			close(closed)
+		}()
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Error("Closing the appender blocked by a collector not reading entries")
+		}
+	})
+	t.Run("Test collector resetting connections", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+		var accepted atomic.Int32
+		go func() {
+			for {
+				connection, e := listener.Accept()
+				if e != nil {
+					return
+				}
+				accepted.Add(1)
+				_ = connection.(*net.TCPConn).SetLinger(0)
+				_ = connection.Close()
+			}
+		}()
+
+		appender := NewNetworkAppender(NetworkConfig{
+			Network:    "tcp",
+			Address:    listener.Addr().String(),
+			Encoder:    TextEncoder{},
+			MinBackoff: 50 * time.Millisecond,
+			MaxBackoff: time.Second,
+		})
+		for deadline := time.Now().Add(300 * time.Millisecond); time.Now().Before(deadline); {
+			appender.Print(&LogEntry{Level: INFO, Message: "reset"})
+			time.Sleep(time.Millisecond)
+		}
+		_ = appender.Close()
+		if count := accepted.Load(); count > 6 {
+			t.Error("Reconnecting without backing off :", count)
+		}
+	})
+}
@@ -6,14 +6,16 @@ package log
 
 import (
 	"io"
+	"reflect"
+	"time"
 )
 
 // constants for date format. Borrowing the same names from standard log package
 const (
-	Ldate         = iota // the date in the local time zone: 2009/01/23
-	Ltime                // the time in the local time zone: 01:23:23
-	Lmicroseconds        // microsecond resolution: 01:23:23.123123.  assumes Ltime.
-	LUTC                 // if Ldate or Ltime is set, use UTC rather than the local time zone
+	Ldate         = 1 << iota // the date in the local time zone: 2009/01/23
+	Ltime                     // the time in the local time zone: 01:23:23
+	Lmicroseconds             // microsecond resolution: 01:23:23.123123.  assumes Ltime.
+	LUTC                      // if Ldate or Ltime is set, use UTC rather than the local time zone
 )
 
 // Log message format constants to use in message header format pattern
@@ -24,6 +26,15 @@ const (
 	LongSource
 	Separator
 	LogLevel
+	Function     // name of the calling function (with receiver type for methods)
+	Package      // import path of the calling package
+	ModuleSource // source file path relative to its module root
+)
+
+// Stack trace formats used by the standard writer
+const (
+	StackTraceIndented = iota // indented block of function and source lines, similar to a panic trace (default)
+	StackTraceJSON            // single line JSON array of frames
 )
 
 // Types of loggers
@@ -51,6 +62,44 @@ type Options interface {
 
 	// WithLogPrefix sets the log prefix format for all levels
 	WithLogPrefix(flags ...uint) Options
+
+	// WithStackTrace sets the logger to capture a stack trace for entries logged at the given level or more severe
+	WithStackTrace(level int) Options
+
+	// WithStackTraceFormat sets the format stack traces are written in by a standard writer logger
+	WithStackTraceFormat(format uint) Options
+
+	// WithLevelSampler sets the sampler deciding which entries of a specific level are logged
+	WithLevelSampler(logLevel int, sampler Sampler) Options
+
+	// WithSampler sets the sampler deciding which entries are logged for all levels
+	WithSampler(sampler Sampler) Options
+
+	// WithSummaryInterval sets the interval between summaries of the entries suppressed by sampling
+	WithSummaryInterval(interval time.Duration) Options
+
+	// WithDeduplication sets the logger to collapse entries repeating the same level and message within the window
+	WithDeduplication(window time.Duration) Options
+
+	// WithRoutes adds routes sending matching entries to other writers or appenders
+	WithRoutes(routes ...Route) Options
+
+	// WithPipeline sets the pipeline of filters and transformers entries go through before being output
+	WithPipeline(pipeline *Pipeline) Options
+
+	// WithRedactor sets the redactor masking sensitive data in entries before they are output
+	WithRedactor(redactor *Redactor) Options
+
+	// WithVerbosity sets the initial verbosity of the logger, enabling the entries logged through V up to that level
+	WithVerbosity(verbosity int) Options
+
+	// WithVModule sets the verbosity of the source files matching patterns, as a comma separated list of
+	// pattern=verbosity pairs (like "db*=2,controllers/*=4"). Panics if the specification is invalid, so
+	// specifications coming from user input should be parsed with ParseVModule and set through WithVModules.
+	WithVModule(spec string) Options
+
+	// WithVModules sets the verbosity of the source files matching the patterns of the given vmodules
+	WithVModules(modules ...VModule) Options
 }
 
 type StandardWriter interface {
@@ -60,16 +109,28 @@ type StandardWriter interface {
 
 type AppendersLogger interface {
 	Options
+	WithAppenders(appenders ...Appender) AppendersLogger
 }
 
 // options holds the configuration for a new logger and provides methods to setup the configurable options
 type options struct {
-	loggerType       uint      // type of logger the options are for
-	dateFlags        int       // format flags for the logger as per the go standard log package
-	failingCriticals bool      // flag setting if a critical log should result in a fatal entry (process exits)
-	startingLevel    int       // the log level the logger should start in
-	levelFormats     [][]uint  // formats used for each of the log levels
-	writer           io.Writer // writer that should be used for a standard writer logger
+	loggerType       uint          // type of logger the options are for
+	dateFlags        int           // format flags for the logger as per the go standard log package
+	failingCriticals bool          // flag setting if a critical log should result in a fatal entry (process exits)
+	startingLevel    int           // the log level the logger should start in
+	levelFormats     [][]uint      // formats used for each of the log levels
+	writer           io.Writer     // writer that should be used for a standard writer logger
+	appenders        []Appender    // appenders that should be used for a synced appenders logger
+	stackTraceLevel  int           // least severe level for which stack traces are captured (UNKNOWN for none)
+	stackTraceFormat uint          // format used to write stack traces by a standard writer logger
+	samplers         []Sampler     // samplers used for each of the log levels
+	summaryInterval  time.Duration // interval between summaries of entries suppressed by sampling
+	dedupWindow      time.Duration // window in which repeated entries are collapsed (none if not positive)
+	routes           []Route       // routes sending matching entries to other writers or appenders
+	pipeline         *Pipeline     // filters and transformers entries go through before being output
+	redactor         *Redactor     // redactor masking sensitive data in entries
+	verbosity        int           // the verbosity the logger should start with
+	vmodules         []VModule     // verbosity of the source files matching patterns
 }
 
 // Standard creates an Options object for standard logging
@@ -80,14 +141,17 @@ func Standard() StandardWriter {
 		failingCriticals: false,
 		startingLevel:    WARNING,
 		levelFormats:     make([][]uint, TRACE+1),
+		stackTraceLevel:  UNKNOWN,
 	}
 }
 
+// SyncedAppenders creates an Options object for a logger handing log entries to a set of appenders
 func SyncedAppenders() AppendersLogger {
 	return &options{
 		loggerType:       syncedAppender,
 		failingCriticals: false,
 		startingLevel:    WARNING,
+		stackTraceLevel:  UNKNOWN,
 	}
 }
 
@@ -97,6 +161,12 @@ func (o *options) WithWriter(writer io.Writer) Options {
 	return o
 }
 
+// WithAppenders adds appenders to a SyncedAppenders logger
+func (o *options) WithAppenders(appenders ...Appender) AppendersLogger {
+	o.appenders = append(o.appenders, appenders...)
+	return o
+}
+
 // DateFlags sets the format flags for the logger
 func (o *options) DateFlags(flags int) Options {
 	o.dateFlags = flags
@@ -122,9 +192,9 @@ func (o *options) WithStartingLevel(startingLevel int) Options {
 }
 
 func validatePrefixFlags(flags []uint) {
-	foundFlags := []bool{false, false, false, false, false, false}
+	foundFlags := make([]bool, ModuleSource+1)
 	for _, flag := range flags {
-		if flag > LogLevel {
+		if flag > ModuleSource {
 			panic("Unknown lof prefix flag")
 		} else if foundFlags[flag] {
 			panic("duplicating  prefix flags")
@@ -154,7 +224,247 @@ func (o *options) WithLogPrefix(flags ...uint) Options {
 	return o
 }
 
+// WithStackTrace sets the logger to capture a stack trace for entries logged at the given level or more severe
+func (o *options) WithStackTrace(level int) Options {
+	o.stackTraceLevel = level
+	return o
+}
+
+// WithStackTraceFormat sets the format stack traces are written in by a standard writer logger
+func (o *options) WithStackTraceFormat(format uint) Options {
+	o.stackTraceFormat = format
+	return o
+}
+
+// WithLevelSampler sets the sampler deciding which entries of a specific level are logged. Panics for negative levels.
+func (o *options) WithLevelSampler(logLevel int, sampler Sampler) Options {
+	if logLevel < 0 {
+		panic("invalid sampler log level")
+	}
+	for len(o.samplers) <= logLevel {
+		o.samplers = append(o.samplers, nil)
+	}
+	o.samplers[logLevel] = sampler
+	return o
+}
+
+// WithSampler sets the sampler deciding which entries are logged for all levels
+func (o *options) WithSampler(sampler Sampler) Options {
+	for len(o.samplers) <= TRACE {
+		o.samplers = append(o.samplers, nil)
+	}
+	for i := range o.samplers {
+		o.samplers[i] = sampler
+	}
+	return o
+}
+
+// WithSummaryInterval sets the interval between summaries of the entries suppressed by sampling
+func (o *options) WithSummaryInterval(interval time.Duration) Options {
+	o.summaryInterval = interval
+	return o
+}
+
+// WithDeduplication sets the logger to collapse entries repeating the same level and message within the window
+func (o *options) WithDeduplication(window time.Duration) Options {
+	o.dedupWindow = window
+	return o
+}
+
+// WithRoutes adds routes sending matching entries to other writers or appenders
+func (o *options) WithRoutes(routes ...Route) Options {
+	o.routes = append(o.routes, routes...)
+	return o
+}
+
+// WithPipeline sets the pipeline of filters and transformers entries go through before being output
+func (o *options) WithPipeline(pipeline *Pipeline) Options {
+	o.pipeline = pipeline
+	return o
+}
+
+// WithRedactor sets the redactor masking sensitive data in entries before they are output
+func (o *options) WithRedactor(redactor *Redactor) Options {
+	o.redactor = redactor
+	return o
+}
+
+// sameSampling checks if the options sample entries the same way as other options
+func (o *options) sameSampling(other *options) bool {
+	return sameSamplers(o.samplers, other.samplers) && o.summaryInterval == other.summaryInterval
+}
+
+// clone creates a copy of the options object which can be changed without affecting the original
+func (o *options) clone() *options {
+	clone := *o
+	clone.levelFormats = make([][]uint, len(o.levelFormats))
+	for i, format := range o.levelFormats {
+		clone.levelFormats[i] = append([]uint(nil), format...)
+	}
+	clone.appenders = append([]Appender(nil), o.appenders...)
+	clone.samplers = append([]Sampler(nil), o.samplers...)
+	clone.routes = append([]Route(nil), o.routes...)
+	clone.vmodules = append([]VModule(nil), o.vmodules...)
+	return &clone
+}
+
+// WithVerbosity sets the initial verbosity of the logger
+func (o *options) WithVerbosity(verbosity int) Options {
+	o.verbosity = verbosity
+	return o
+}
+
+// WithVModule sets the verbosity of the source files matching patterns
+func (o *options) WithVModule(spec string) Options {
+	modules, e := ParseVModule(spec)
+	if e != nil {
+		panic(e)
+	}
+	o.vmodules = modules
+	return o
+}
+
+// WithVModules sets the verbosity of the source files matching the patterns of the given vmodules
+func (o *options) WithVModules(modules ...VModule) Options {
+	o.vmodules = append([]VModule(nil), modules...)
+	return o
+}
+
 // equals compares if the options object is an exact match to another options object
 func (o *options) equals(options *options) bool {
-	return o.failingCriticals == options.failingCriticals && o.dateFlags == options.dateFlags && o.startingLevel == options.startingLevel
+	return o.difference(options) == ""
+}
+
+// difference returns the name of the first option differing from another options object, or an empty string if
+// they match. Writers, appenders, samplers, routes, pipelines and redactors are compared by identity.
+func (o *options) difference(options *options) string {
+	switch {
+	case o.loggerType != options.loggerType:
+		return "logger type"
+	case o.failingCriticals != options.failingCriticals:
+		return "failing criticals"
+	case o.dateFlags != options.dateFlags:
+		return "date flags"
+	case o.startingLevel != options.startingLevel:
+		return "starting level"
+	case !equalFormats(o.levelFormats, options.levelFormats):
+		return "level formats"
+	case !sameValue(o.writer, options.writer):
+		return "writer"
+	case !sameAppenders(o.appenders, options.appenders):
+		return "appenders"
+	case o.stackTraceLevel != options.stackTraceLevel:
+		return "stack trace level"
+	case o.stackTraceFormat != options.stackTraceFormat:
+		return "stack trace format"
+	case !sameSamplers(o.samplers, options.samplers):
+		return "samplers"
+	case o.summaryInterval != options.summaryInterval:
+		return "summary interval"
+	case o.dedupWindow != options.dedupWindow:
+		return "deduplication window"
+	case !sameRoutes(o.routes, options.routes):
+		return "routes"
+	case o.pipeline != options.pipeline:
+		return "pipeline"
+	case o.redactor != options.redactor:
+		return "redactor"
+	case o.verbosity != options.verbosity:
+		return "verbosity"
+	case !equalVModules(o.vmodules, options.vmodules):
+		return "vmodules"
+	}
+	return ""
+}
+
+// equalFormats compares the header formats of all levels
+func equalFormats(formats [][]uint, otherFormats [][]uint) bool {
+	if len(formats) != len(otherFormats) {
+		return false
+	}
+	for i, format := range formats {
+		if len(format) != len(otherFormats[i]) {
+			return false
+		}
+		for j, flag := range format {
+			if flag != otherFormats[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalVModules compares the patterns and verbosity of two lists of vmodules
+func equalVModules(modules []VModule, otherModules []VModule) bool {
+	if len(modules) != len(otherModules) {
+		return false
+	}
+	for i, module := range modules {
+		if module != otherModules[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameAppenders compares two lists of appenders by identity
+func sameAppenders(appenders []Appender, otherAppenders []Appender) bool {
+	if len(appenders) != len(otherAppenders) {
+		return false
+	}
+	for i, appender := range appenders {
+		if !sameValue(appender, otherAppenders[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameSamplers compares the samplers of all levels by identity
+func sameSamplers(samplers []Sampler, otherSamplers []Sampler) bool {
+	if len(samplers) != len(otherSamplers) {
+		return false
+	}
+	for i, sampler := range samplers {
+		if !sameValue(sampler, otherSamplers[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameRoutes compares two lists of routes, their predicates, writers, encoders and appenders by identity
+func sameRoutes(routes []Route, otherRoutes []Route) bool {
+	if len(routes) != len(otherRoutes) {
+		return false
+	}
+	for i, route := range routes {
+		other := otherRoutes[i]
+		if !sameValue(route.Match, other.Match) || !sameValue(route.Writer, other.Writer) ||
+			!sameValue(route.Encoder, other.Encoder) || !sameAppenders(route.Appenders, other.Appenders) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameValue compares two values by identity. Comparable values are compared with ==, maps and slices by the pointer
+// they hold and functions by their code pointer (closures created by the same function literal are the same).
+func sameValue(value interface{}, other interface{}) bool {
+	if value == nil || other == nil {
+		return value == nil && other == nil
+	}
+	valueType := reflect.TypeOf(value)
+	if valueType != reflect.TypeOf(other) {
+		return false
+	}
+	if valueType.Comparable() {
+		return value == other
+	}
+	switch valueType.Kind() {
+	case reflect.Func, reflect.Map, reflect.Slice:
+		return reflect.ValueOf(value).Pointer() == reflect.ValueOf(other).Pointer()
+	}
+	return false
 }
@@ -0,0 +1,224 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default OpenTelemetry bridge settings
+const (
+	DefaultOTelScope  = "github.com/gomatbase/go-log"
+	DefaultTraceIdKey = "trace_id"
+	DefaultSpanIdKey  = "span_id"
+)
+
+// otelSeverities dictionary to translate log levels into OpenTelemetry severity numbers
+var otelSeverities = []int{
+	CRITICAL: 21, // FATAL
+	ERROR:    17, // ERROR
+	WARNING:  13, // WARN
+	INFO:     9,  // INFO
+	DEBUG:    5,  // DEBUG
+	TRACE:    1,  // TRACE
+}
+
+// OTelArrayValue is an array value of the OpenTelemetry data model
+type OTelArrayValue struct {
+	Values []OTelAnyValue `json:"values"`
+}
+
+// OTelAnyValue is a value of the OpenTelemetry data model, holding only one of its fields. As per OTLP/JSON, 64 bit
+// integers are represented as strings.
+type OTelAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *OTelArrayValue `json:"arrayValue,omitempty"`
+}
+
+// OTelKeyValue is an attribute of the OpenTelemetry data model
+type OTelKeyValue struct {
+	Key   string       `json:"key"`
+	Value OTelAnyValue `json:"value"`
+}
+
+// OTelLogRecord is a log record of the OpenTelemetry logs data model, in its OTLP/JSON representation
+type OTelLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 OTelAnyValue   `json:"body"`
+	Attributes           []OTelKeyValue `json:"attributes,omitempty"`
+	TraceId              string         `json:"traceId,omitempty"`
+	SpanId               string         `json:"spanId,omitempty"`
+}
+
+// otelValue converts a go value into an OpenTelemetry value. Values without a matching type are converted to their
+// default format string.
+func otelValue(value interface{}) OTelAnyValue {
+	switch v := value.(type) {
+	case string:
+		return OTelAnyValue{StringValue: &v}
+	case bool:
+		return OTelAnyValue{BoolValue: &v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s := fmt.Sprint(v)
+		return OTelAnyValue{IntValue: &s}
+	case float32:
+		f := float64(v)
+		return OTelAnyValue{DoubleValue: &f}
+	case float64:
+		return OTelAnyValue{DoubleValue: &v}
+	case []string:
+		array := &OTelArrayValue{Values: make([]OTelAnyValue, len(v))}
+		for i := range v {
+			array.Values[i] = otelValue(v[i])
+		}
+		return OTelAnyValue{ArrayValue: array}
+	case []interface{}:
+		array := &OTelArrayValue{Values: make([]OTelAnyValue, len(v))}
+		for i := range v {
+			array.Values[i] = otelValue(v[i])
+		}
+		return OTelAnyValue{ArrayValue: array}
+	case error:
+		s := v.Error()
+		return OTelAnyValue{StringValue: &s}
+	default:
+		s := fmt.Sprint(v)
+		return OTelAnyValue{StringValue: &s}
+	}
+}
+
+// otelId converts a trace or span id field value into its hex representation
+func otelId(value interface{}) string {
+	switch id := value.(type) {
+	case [16]byte:
+		return hex.EncodeToString(id[:])
+	case [8]byte:
+		return hex.EncodeToString(id[:])
+	case []byte:
+		return hex.EncodeToString(id)
+	default:
+		return strings.ToLower(fmt.Sprint(id))
+	}
+}
+
+// OTelEncoder encodes entries as OpenTelemetry log records in their OTLP/JSON representation. Fields with the trace
+// and span id keys become the record's trace context, the other fields become attributes alongside the logger name,
+// the source information and the first logged error.
+type OTelEncoder struct {
+	TraceIdKey string // key of the field holding the trace id (defaults to DefaultTraceIdKey)
+	SpanIdKey  string // key of the field holding the span id (defaults to DefaultSpanIdKey)
+}
+
+// Record converts an entry into an OpenTelemetry log record
+func (oe OTelEncoder) Record(entry *LogEntry) *OTelLogRecord {
+	traceIdKey, spanIdKey := oe.TraceIdKey, oe.SpanIdKey
+	if len(traceIdKey) == 0 {
+		traceIdKey = DefaultTraceIdKey
+	}
+	if len(spanIdKey) == 0 {
+		spanIdKey = DefaultSpanIdKey
+	}
+
+	message := strings.TrimSuffix(entry.Message, "\n")
+	record := &OTelLogRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       1,
+		SeverityText:         LevelName(entry.Level),
+		Body:                 OTelAnyValue{StringValue: &message},
+	}
+	if entry.Level >= 0 && entry.Level < len(otelSeverities) {
+		record.SeverityNumber = otelSeverities[entry.Level]
+	}
+	attribute := func(key string, value interface{}) {
+		record.Attributes = append(record.Attributes, OTelKeyValue{Key: key, Value: otelValue(value)})
+	}
+	if len(entry.Logger) > 0 {
+		attribute("logger.name", entry.Logger)
+	}
+	if entry.Source != nil {
+		attribute("code.filepath", *entry.Source)
+		attribute("code.lineno", entry.Line)
+		attribute("code.function", entry.Function)
+		attribute("code.namespace", entry.Package)
+	}
+	if len(entry.Errors) > 0 {
+		attribute("exception.type", entry.Errors[0].Type)
+		attribute("exception.message", entry.Errors[0].Message)
+	}
+	if len(entry.Stack) > 0 {
+		var stack []byte
+		stacktobuf(&stack, entry.Stack, StackTraceIndented)
+		attribute("exception.stacktrace", string(stack))
+	}
+	for _, field := range entry.Fields {
+		switch field.Key {
+		case traceIdKey:
+			record.TraceId = otelId(field.Value)
+		case spanIdKey:
+			record.SpanId = otelId(field.Value)
+		default:
+			attribute(field.Key, field.Value)
+		}
+	}
+	return record
+}
+
+// Encode appends the entry as an OTLP/JSON log record followed by a new line
+func (oe OTelEncoder) Encode(buf []byte, entry *LogEntry) []byte {
+	encoded, _ := json.Marshal(oe.Record(entry))
+	return append(append(buf, encoded...), '\n')
+}
+
+// OTelConfig holds the configuration of an OpenTelemetry exporter
+type OTelConfig struct {
+	HTTPConfig                        // batching and delivery settings, URL being the OTLP/HTTP logs endpoint (like http://collector:4318/v1/logs)
+	Resource   map[string]interface{} // resource attributes (like service.name)
+	Scope      string                 // instrumentation scope name (defaults to DefaultOTelScope)
+	TraceIdKey string                 // key of the field holding the trace id (defaults to DefaultTraceIdKey)
+	SpanIdKey  string                 // key of the field holding the span id (defaults to DefaultSpanIdKey)
+}
+
+// OTelExporter appender exporting entries in batches as OpenTelemetry log records to an OTLP/HTTP endpoint, using
+// the JSON encoding
+type OTelExporter struct {
+	*HTTPAppender
+}
+
+// NewOTelExporter creates an OpenTelemetry exporter
+func NewOTelExporter(config OTelConfig) *OTelExporter {
+	if len(config.Scope) == 0 {
+		config.Scope = DefaultOTelScope
+	}
+	keys := make([]string, 0, len(config.Resource))
+	for key := range config.Resource {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	resource := make([]OTelKeyValue, len(keys))
+	for i, key := range keys {
+		resource[i] = OTelKeyValue{Key: key, Value: otelValue(config.Resource[key])}
+	}
+	encodedResource, _ := json.Marshal(resource)
+	encodedScope, _ := json.Marshal(config.Scope)
+
+	prefix := `{"resourceLogs":[{"resource":{"attributes":` + string(encodedResource) +
+		`},"scopeLogs":[{"scope":{"name":` + string(encodedScope) + `},"logRecords":`
+	config.HTTPConfig.Format = BatchJSONArray
+	config.HTTPConfig.Encoder = OTelEncoder{TraceIdKey: config.TraceIdKey, SpanIdKey: config.SpanIdKey}
+	return &OTelExporter{newHTTPAppender(config.HTTPConfig, [2][]byte{[]byte(prefix), []byte(`}]}]}`)})}
+}
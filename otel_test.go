@@ -0,0 +1,78 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type otlpRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []OTelKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []OTelLogRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+func TestOTelExporter(t *testing.T) {
+	server := newIngestionServer(0)
+	defer server.Close()
+
+	exporter := NewOTelExporter(OTelConfig{
+		HTTPConfig: HTTPConfig{URL: server.URL + "/v1/logs", FlushInterval: time.Hour},
+		Resource:   map[string]interface{}{"service.name": "billing", "service.instance": 3},
+	})
+	exporter.Print(&LogEntry{
+		Logger:    "DB",
+		Timestamp: time.Unix(1624617015, 5),
+		Level:     ERROR,
+		Message:   "query failed\n",
+		Fields:    []Field{F("trace_id", "4BF92F3577B34DA6A3CE929D0E0E4736"), F("span_id", [8]byte{0, 240, 103, 170, 11, 169, 2, 183}), F("rows", 3), Err(errors.New("timeout"))},
+		Errors:    []ErrorInfo{{Message: "timeout", Type: "*errors.errorString"}},
+	})
+	if e := exporter.Close(); e != nil {
+		t.Fatal("Unexpected error closing exporter :", e)
+	}
+
+	if len(server.bodies) != 1 {
+		t.Fatalf("Unexpected requests: %q", server.bodies)
+	}
+	request := &otlpRequest{}
+	if e := json.Unmarshal([]byte(server.bodies[0]), request); e != nil {
+		t.Fatalf("Invalid OTLP request %q: %v", server.bodies[0], e)
+	}
+	resource := request.ResourceLogs[0].Resource.Attributes
+	if len(resource) != 2 || resource[0].Key != "service.instance" || *resource[0].Value.IntValue != "3" || *resource[1].Value.StringValue != "billing" {
+		t.Error("Unexpected resource attributes :", resource)
+	}
+	scope := request.ResourceLogs[0].ScopeLogs[0]
+	if scope.Scope.Name != DefaultOTelScope || len(scope.LogRecords) != 1 {
+		t.Fatal("Unexpected scope logs :", scope)
+	}
+	record := scope.LogRecords[0]
+	if record.TimeUnixNano != "1624617015000000005" || record.SeverityNumber != 17 || record.SeverityText != "ERROR" || *record.Body.StringValue != "query failed" {
+		t.Error("Unexpected log record :", record)
+	}
+	if record.TraceId != "4bf92f3577b34da6a3ce929d0e0e4736" || record.SpanId != "00f067aa0ba902b7" {
+		t.Error("Unexpected trace context :", record.TraceId, record.SpanId)
+	}
+	attributes := make(map[string]OTelAnyValue)
+	for _, attribute := range record.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	if len(attributes) != 5 || *attributes["logger.name"].StringValue != "DB" || *attributes["rows"].IntValue != "3" ||
+		*attributes["error"].StringValue != "timeout" || *attributes["exception.type"].StringValue != "*errors.errorString" {
+		t.Error("Unexpected attributes :", record.Attributes)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// levelOverride a temporary level set on a logger
+type levelOverride struct {
+	level int
+}
+
+// levelOverrides keeps track of the temporary level overrides of a logger. Overrides may be nested and restored in
+// any order, the level of the logger being the level of the latest active override or, once all are restored, the
+// level the logger had before the first one. Setting the level explicitly while overridden discards the overrides,
+// even if it's set to the overridden level.
+type levelOverrides struct {
+	base   int              // level of the logger before the first active override
+	active []*levelOverride // active overrides, latest last
+	mutex  sync.Mutex
+}
+
+// set explicitly sets the level of the logger, discarding the active overrides
+func (lo *levelOverrides) set(current *atomic.Int32, level int) {
+	lo.mutex.Lock()
+	defer lo.mutex.Unlock()
+
+	lo.active = nil
+	current.Store(int32(level))
+}
+
+// push overrides the level of the logger, returning the function restoring it. Calling it more than once has no
+// effect.
+func (lo *levelOverrides) push(current *atomic.Int32, level int) func() {
+	lo.mutex.Lock()
+	defer lo.mutex.Unlock()
+
+	if len(lo.active) == 0 {
+		lo.base = int(current.Load())
+	}
+	override := &levelOverride{level: level}
+	lo.active = append(lo.active, override)
+	current.Store(int32(level))
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			lo.restore(current, override)
+		})
+	}
+}
+
+// override overrides the level of the logger for the given duration, returning the function restoring it earlier
+func (lo *levelOverrides) override(current *atomic.Int32, level int, duration time.Duration) func() {
+	restore := lo.push(current, level)
+	time.AfterFunc(duration, restore)
+	return restore
+}
+
+// restore removes the override, setting the logger to the level of the latest remaining override or to the level it
+// had before the overrides. Does nothing if the override was discarded by an explicitly set level.
+func (lo *levelOverrides) restore(current *atomic.Int32, override *levelOverride) {
+	lo.mutex.Lock()
+	defer lo.mutex.Unlock()
+
+	found := false
+	for i, active := range lo.active {
+		if active == override {
+			lo.active = append(lo.active[:i], lo.active[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+	if len(lo.active) > 0 {
+		current.Store(int32(lo.active[len(lo.active)-1].level))
+	} else {
+		current.Store(int32(lo.base))
+	}
+}
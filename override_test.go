@@ -0,0 +1,122 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLevelOverrides(t *testing.T) {
+	registry := NewRegistry()
+	logger, _ := registry.Get("Overridden")
+
+	t.Run("Test nested overrides", func(t *testing.T) {
+		restoreDebug := logger.PushLevel(DEBUG)
+		restoreTrace, e := registry.PushLevel("Overridden", TRACE)
+		if e != nil {
+			t.Error("Pushing the level of an existing logger failed :", e)
+		}
+		if logger.Level() != TRACE {
+			t.Error("Nested override not applied :", logger.Level())
+		}
+		restoreDebug()
+		if logger.Level() != TRACE {
+			t.Error("Restoring an outer override should keep the inner one :", logger.Level())
+		}
+		restoreTrace()
+		restoreTrace()
+		if logger.Level() != WARNING {
+			t.Error("Restoring all overrides should restore the previous level :", logger.Level())
+		}
+	})
+
+	t.Run("Test explicit level discarding overrides", func(t *testing.T) {
+		restore := logger.PushLevel(TRACE)
+		logger.SetLevel(ERROR)
+		restore()
+		if logger.Level() != ERROR {
+			t.Error("Explicitly set level should not be restored :", logger.Level())
+		}
+		logger.SetLevel(WARNING)
+	})
+
+	t.Run("Test explicit level equal to the overridden level", func(t *testing.T) {
+		restore := logger.PushLevel(TRACE)
+		logger.SetLevel(TRACE)
+		restore()
+		if logger.Level() != TRACE {
+			t.Error("Explicitly set level should not be restored :", logger.Level())
+		}
+		logger.SetLevel(WARNING)
+	})
+
+	t.Run("Test timed override", func(t *testing.T) {
+		if _, e := registry.OverrideLevel("Overridden", DEBUG, 10*time.Millisecond); e != nil {
+			t.Error("Overriding the level of an existing logger failed :", e)
+		}
+		if logger.Level() != DEBUG {
+			t.Error("Timed override not applied :", logger.Level())
+		}
+		deadline := time.Now().Add(time.Second)
+		for logger.Level() != WARNING && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if logger.Level() != WARNING {
+			t.Error("Timed override not restored :", logger.Level())
+		}
+		restore, _ := registry.OverrideLevel("Overridden", DEBUG, time.Hour)
+		restore()
+		if logger.Level() != WARNING {
+			t.Error("Timed override not restored early :", logger.Level())
+		}
+		if restore, e := registry.OverrideLevel("Missing", DEBUG, time.Second); e != ErrLoggerDoesNotExist {
+			t.Error("Overriding the level of a missing logger should fail :", e)
+		} else {
+			restore()
+		}
+		if restore, e := registry.PushLevel("Missing", DEBUG); e != ErrLoggerDoesNotExist {
+			t.Error("Pushing the level of a missing logger should fail :", e)
+		} else {
+			restore()
+		}
+	})
+}
+
+func TestPackageLevelOverrides(t *testing.T) {
+	resetLoggers()
+	logger, _ := Get("Overridden")
+
+	t.Run("Test deferred restore", func(t *testing.T) {
+		func() {
+			defer PushLevel("Overridden", TRACE)()
+			if logger.Level() != TRACE {
+				t.Error("Override not applied :", logger.Level())
+			}
+		}()
+		if logger.Level() != WARNING {
+			t.Error("Deferred restore not applied :", logger.Level())
+		}
+		OverrideLevel("Overridden", DEBUG, time.Hour)()
+		PushLevel("Missing", DEBUG)()
+		if logger.Level() != WARNING {
+			t.Error("Timed override not restored :", logger.Level())
+		}
+	})
+
+	t.Run("Test missing logger errors", func(t *testing.T) {
+		if _, e := PushLevelE("Missing", DEBUG); e != ErrLoggerDoesNotExist {
+			t.Error("Pushing the level of a missing logger should fail :", e)
+		}
+		if _, e := OverrideLevelE("Missing", DEBUG, time.Second); e != ErrLoggerDoesNotExist {
+			t.Error("Overriding the level of a missing logger should fail :", e)
+		}
+		if restore, e := PushLevelE("Overridden", DEBUG); e != nil || logger.Level() != DEBUG {
+			t.Error("Pushing the level of an existing logger failed :", e)
+		} else {
+			restore()
+		}
+	})
+}
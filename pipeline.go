@@ -0,0 +1,166 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"unicode/utf8"
+)
+
+// Filter decides which entries go through a pipeline
+type Filter interface {
+	// Filter returns true if the entry should be kept
+	Filter(entry *LogEntry) bool
+}
+
+// Transformer transforms entries going through a pipeline
+type Transformer interface {
+	// Transform returns the transformed entry. The entry may be modified in place, or nil returned to drop it.
+	Transform(entry *LogEntry) *LogEntry
+}
+
+// FilterFunc adapts a function into a Filter
+type FilterFunc func(entry *LogEntry) bool
+
+// Filter calls the function
+func (f FilterFunc) Filter(entry *LogEntry) bool {
+	return f(entry)
+}
+
+// TransformerFunc adapts a function into a Transformer
+type TransformerFunc func(entry *LogEntry) *LogEntry
+
+// Transform calls the function
+func (f TransformerFunc) Transform(entry *LogEntry) *LogEntry {
+	return f(entry)
+}
+
+// Pipeline is a sequence of filters and transformers entries go through before reaching a writer or appender.
+// Entries are copied before being transformed, so pipelines of different appenders don't interfere.
+type Pipeline struct {
+	stages []Transformer
+}
+
+// NewPipeline creates an empty pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Filter adds filter stages to the pipeline
+func (p *Pipeline) Filter(filters ...Filter) *Pipeline {
+	for _, filter := range filters {
+		filter := filter
+		p.stages = append(p.stages, TransformerFunc(func(entry *LogEntry) *LogEntry {
+			if filter.Filter(entry) {
+				return entry
+			}
+			return nil
+		}))
+	}
+	return p
+}
+
+// Transform adds transformer stages to the pipeline
+func (p *Pipeline) Transform(transformers ...Transformer) *Pipeline {
+	p.stages = append(p.stages, transformers...)
+	return p
+}
+
+// Process runs the entry through the pipeline, returning the resulting entry or nil if it was dropped
+func (p *Pipeline) Process(entry *LogEntry) *LogEntry {
+	if p == nil || len(p.stages) == 0 {
+		return entry
+	}
+	copied := *entry
+	copied.Fields = append([]Field(nil), entry.Fields...)
+	processed := &copied
+	for _, stage := range p.stages {
+		if processed = stage.Transform(processed); processed == nil {
+			return nil
+		}
+	}
+	return processed
+}
+
+// To decorates an appender so that entries go through the pipeline before reaching it
+func (p *Pipeline) To(appender Appender) Appender {
+	return &pipelineAppender{pipeline: p, appender: appender}
+}
+
+// pipelineAppender appender decorator running entries through a pipeline
+type pipelineAppender struct {
+	pipeline *Pipeline
+	appender Appender
+}
+
+// Print hands the entry to the decorated appender if it's not dropped by the pipeline
+func (pa *pipelineAppender) Print(entry *LogEntry) {
+	if processed := pa.pipeline.Process(entry); processed != nil {
+		pa.appender.Print(processed)
+	}
+}
+
+// decorated returns the appender decorated by the pipeline
+func (pa *pipelineAppender) decorated() Appender {
+	return pa.appender
+}
+
+// DropMatching creates a filter dropping entries with messages matching the regular expression
+func DropMatching(expression *regexp.Regexp) Filter {
+	return FilterFunc(func(entry *LogEntry) bool {
+		return !expression.MatchString(entry.Message)
+	})
+}
+
+// Enrich creates a transformer adding the given fields to every entry
+func Enrich(fields ...Field) Transformer {
+	return TransformerFunc(func(entry *LogEntry) *LogEntry {
+		entry.Fields = append(entry.Fields, fields...)
+		return entry
+	})
+}
+
+// ProcessFields returns fields describing the running process: hostname, pid and the version of the main module
+func ProcessFields() []Field {
+	hostname, _ := os.Hostname()
+	fields := []Field{F("hostname", hostname), F("pid", os.Getpid())}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fields = append(fields, F("version", info.Main.Version))
+	}
+	return fields
+}
+
+// RemapLevel creates a transformer changing the level of entries logged at one level to another
+func RemapLevel(from int, to int) Transformer {
+	return TransformerFunc(func(entry *LogEntry) *LogEntry {
+		if entry.Level == from {
+			entry.Level = to
+		}
+		return entry
+	})
+}
+
+// Truncate creates a transformer truncating messages longer than maxLength bytes, without splitting characters, and
+// marking them with a trailing "...". The trailing newline of println-style messages is not counted and is kept. A
+// negative maxLength is handled as 0, keeping only the trailing "...".
+func Truncate(maxLength int) Transformer {
+	if maxLength < 0 {
+		maxLength = 0
+	}
+	return TransformerFunc(func(entry *LogEntry) *LogEntry {
+		message := strings.TrimSuffix(entry.Message, "\n")
+		if len(message) > maxLength {
+			cut := maxLength
+			for cut > 0 && !utf8.RuneStart(message[cut]) {
+				cut--
+			}
+			entry.Message = message[:cut] + "..." + entry.Message[len(message):]
+		}
+		return entry
+	})
+}
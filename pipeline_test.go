@@ -0,0 +1,83 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Run("Test appender pipelines", func(t *testing.T) {
+		plain, processed := &recordingAppender{}, &recordingAppender{}
+		pipeline := NewPipeline().
+			Filter(DropMatching(regexp.MustCompile("^health"))).
+			Transform(Enrich(F("build", "1.2.3")), RemapLevel(ERROR, WARNING), Truncate(8))
+		logger, _ := newLogger("PIPELINE", SyncedAppenders().WithAppenders(plain, pipeline.To(processed)).(*options))
+
+		logger.Error("health check ok")
+		logger.Error("connection refused")
+
+		if len(plain.entries) != 2 {
+			t.Fatal("Unexpected entries without pipeline :", len(plain.entries))
+		}
+		if entry := plain.entries[1]; entry.Level != ERROR || entry.Message != "connection refused\n" || len(entry.Fields) != 0 {
+			t.Error("Entries without pipeline modified :", entry)
+		}
+		if len(processed.entries) != 1 {
+			t.Fatal("Unexpected entries with pipeline :", len(processed.entries))
+		}
+		if entry := processed.entries[0]; entry.Level != WARNING || entry.Message != "connecti...\n" || len(entry.Fields) != 1 || entry.Fields[0] != F("build", "1.2.3") {
+			t.Error("Unexpected processed entry :", entry)
+		}
+	})
+
+	t.Run("Test logger pipeline", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		pipeline := NewPipeline().Filter(FilterFunc(func(entry *LogEntry) bool { return entry.Level != INFO }))
+		logger, _ := newLogger("PIPELINE", Standard().WithWriter(buffer).WithStartingLevel(INFO).WithPipeline(pipeline).(*options))
+
+		logger.Warning("WRN")
+		logger.Info("INF")
+
+		if buffer.String() != "WRN\n" {
+			t.Errorf("Unexpected output: %q", buffer.String())
+		}
+	})
+
+	t.Run("Test truncating multi-byte characters", func(t *testing.T) {
+		entry := Truncate(4).Transform(&LogEntry{Message: "añão"})
+		if entry.Message != "añ..." {
+			t.Errorf("Unexpected truncated message: %q", entry.Message)
+		}
+	})
+
+	t.Run("Test truncating println-style messages", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger, _ := newLogger("PIPELINE", Standard().WithWriter(buffer).WithPipeline(NewPipeline().Transform(Truncate(5))).(*options))
+		logger.Warning("hello")
+		logger.Warningf("hello")
+		logger.Warning("hello world")
+		if buffer.String() != "hello\nhello\nhello...\n" {
+			t.Errorf("Unexpected truncated output: %q", buffer.String())
+		}
+	})
+
+	t.Run("Test truncating to a negative length", func(t *testing.T) {
+		entry := Truncate(-1).Transform(&LogEntry{Message: "message"})
+		if entry.Message != "..." {
+			t.Errorf("Unexpected truncated message: %q", entry.Message)
+		}
+	})
+
+	t.Run("Test process fields", func(t *testing.T) {
+		fields := ProcessFields()
+		if len(fields) != 3 || fields[1] != F("pid", os.Getpid()) {
+			t.Error("Unexpected process fields :", fields)
+		}
+	})
+}
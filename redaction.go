@@ -0,0 +1,284 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultMask is the default replacement of redacted values
+const DefaultMask = "[REDACTED]"
+
+// DeniedFieldCount is the redaction count name of field values redacted for their key
+const DeniedFieldCount = "denied-field"
+
+// Names of the built-in detectors, as used in redaction counts
+const (
+	CreditCardDetector   = "credit-card"
+	JWTDetector          = "jwt"
+	BearerTokenDetector  = "bearer-token"
+	AWSAccessKeyDetector = "aws-access-key"
+	AWSSecretKeyDetector = "aws-secret-key"
+	EmailDetector        = "email"
+)
+
+// maxRedactionDepth is the depth of nested maps and slices walked when redacting field values. Deeper values are
+// replaced by the mask, which also stops self-referencing values.
+const maxRedactionDepth = 10
+
+// DefaultDeniedFields are the field keys whose values are always redacted by the default redactor
+var DefaultDeniedFields = []string{"password", "passwd", "secret", "authorization", "token", "api_key", "apikey"}
+
+// detector finds sensitive data in text. If the expression has groups, only the first group of a match is masked.
+type detector struct {
+	name       string
+	expression *regexp.Regexp
+	validate   func(match string) bool // optional validation of a match, reducing false positives
+}
+
+// builtInDetectors detectors of the default redactor
+var builtInDetectors = []detector{
+	{name: JWTDetector, expression: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+	{name: BearerTokenDetector, expression: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`)},
+	{name: AWSAccessKeyDetector, expression: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: AWSSecretKeyDetector, expression: regexp.MustCompile(`(?i)aws_?secret_?access_?key\W{0,3}([A-Za-z0-9/+=]{40})`)},
+	{name: CreditCardDetector, expression: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), validate: luhn},
+	{name: EmailDetector, expression: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+}
+
+// luhn validates a card number with the Luhn checksum, ignoring separators
+func luhn(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// Redactor masks sensitive data in the messages, fields and errors of entries. Used as a logger option, entries are
+// redacted before reaching any writer or appender. It may also be used as a pipeline transformer. The number of
+// redactions is kept by detector for auditing.
+type Redactor struct {
+	detectors []detector
+	denied    map[string]bool
+	mask      string
+	counts    map[string]uint64
+	mutex     sync.Mutex
+}
+
+// NewRedactor creates a redactor without detectors or denied fields
+func NewRedactor() *Redactor {
+	return &Redactor{
+		denied: make(map[string]bool),
+		mask:   DefaultMask,
+		counts: make(map[string]uint64),
+	}
+}
+
+// DefaultRedactor creates a redactor with the built-in detectors (credit card numbers, JWTs, bearer tokens, AWS keys
+// and emails) and the default denied fields
+func DefaultRedactor() *Redactor {
+	r := NewRedactor().WithDeniedFields(DefaultDeniedFields...)
+	r.detectors = append(r.detectors, builtInDetectors...)
+	return r
+}
+
+// WithDetector adds a detector masking matches of the regular expression. If the expression has groups, only the
+// first group of each match is masked.
+func (r *Redactor) WithDetector(name string, expression *regexp.Regexp) *Redactor {
+	r.detectors = append(r.detectors, detector{name: name, expression: expression})
+	return r
+}
+
+// WithDeniedFields adds field keys (case-insensitive) whose values are always masked
+func (r *Redactor) WithDeniedFields(keys ...string) *Redactor {
+	for _, key := range keys {
+		r.denied[strings.ToLower(key)] = true
+	}
+	return r
+}
+
+// WithMask sets the replacement of redacted values
+func (r *Redactor) WithMask(mask string) *Redactor {
+	r.mask = mask
+	return r
+}
+
+// Counts returns the number of redactions made by each detector, and by DeniedFieldCount for denied fields
+func (r *Redactor) Counts() map[string]uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	counts := make(map[string]uint64, len(r.counts))
+	for name, count := range r.counts {
+		counts[name] = count
+	}
+	return counts
+}
+
+// count adds redactions to the count of a detector
+func (r *Redactor) count(name string, redactions int) {
+	r.mutex.Lock()
+	r.counts[name] += uint64(redactions)
+	r.mutex.Unlock()
+}
+
+// Redact masks the sensitive data found in the text by the redactor's detectors
+func (r *Redactor) Redact(text string) string {
+	return r.redact(text, true)
+}
+
+// redact masks the sensitive data found in the text, adding the redactions to the counts if counted
+func (r *Redactor) redact(text string, counted bool) string {
+	for _, d := range r.detectors {
+		redactions := 0
+		text = d.expression.ReplaceAllStringFunc(text, func(match string) string {
+			if d.validate != nil && !d.validate(match) {
+				return match
+			}
+			redactions++
+			if d.expression.NumSubexp() > 0 {
+				if group := d.expression.FindStringSubmatchIndex(match); len(group) > 3 && group[2] >= 0 {
+					return match[:group[2]] + r.mask + match[group[3]:]
+				}
+			}
+			return r.mask
+		})
+		if counted && redactions > 0 {
+			r.count(d.name, redactions)
+		}
+	}
+	return text
+}
+
+// redactErrors redacts the messages of the error information and its causes. Logged errors are also part of the
+// message or fields of the entry, where their redactions are already counted, so they're not counted again.
+func (r *Redactor) redactErrors(infos []ErrorInfo) []ErrorInfo {
+	if len(infos) == 0 {
+		return infos
+	}
+	redacted := make([]ErrorInfo, len(infos))
+	for i, info := range infos {
+		info.Message = r.redact(info.Message, false)
+		info.Causes = r.redactErrors(info.Causes)
+		redacted[i] = info
+	}
+	return redacted
+}
+
+// redactValue redacts a field value, returning the redacted value and true if it held sensitive data. Text values
+// (strings, errors and stringers) are replaced by their redacted text, formatted as fmt does. Maps with string keys
+// and slices are walked up to maxRedactionDepth, masking the values of denied keys, and are replaced by a redacted
+// copy. Any other value is replaced by its redacted text representation.
+func (r *Redactor) redactValue(value interface{}, depth int) (interface{}, bool) {
+	var text string
+	switch v := value.(type) {
+	case nil, bool:
+		return value, false
+	case string:
+		text = v
+	case error, fmt.Stringer:
+		text = fmt.Sprint(value)
+	default:
+		reflected := reflect.ValueOf(value)
+		switch reflected.Kind() {
+		case reflect.Map:
+			if reflected.Type().Key().Kind() == reflect.String {
+				return r.redactMap(reflected, depth)
+			}
+		case reflect.Slice, reflect.Array:
+			if reflected.Type().Elem().Kind() != reflect.Uint8 {
+				return r.redactSlice(reflected, depth)
+			}
+		}
+		text = fmt.Sprint(value)
+	}
+	if redacted := r.Redact(text); redacted != text {
+		return redacted, true
+	}
+	return value, false
+}
+
+// redactMap redacts the values of a map with string keys, returning a redacted copy if any value was redacted
+func (r *Redactor) redactMap(value reflect.Value, depth int) (interface{}, bool) {
+	if depth >= maxRedactionDepth {
+		return r.mask, true
+	}
+	redacted := make(map[string]interface{}, value.Len())
+	changed := false
+	for iterator := value.MapRange(); iterator.Next(); {
+		key := iterator.Key().String()
+		if r.denied[strings.ToLower(key)] {
+			r.count(DeniedFieldCount, 1)
+			redacted[key] = r.mask
+			changed = true
+		} else {
+			var redactedElement bool
+			redacted[key], redactedElement = r.redactValue(iterator.Value().Interface(), depth+1)
+			changed = changed || redactedElement
+		}
+	}
+	if !changed {
+		return value.Interface(), false
+	}
+	return redacted, true
+}
+
+// redactSlice redacts the elements of a slice or array, returning a redacted copy if any element was redacted
+func (r *Redactor) redactSlice(value reflect.Value, depth int) (interface{}, bool) {
+	if depth >= maxRedactionDepth {
+		return r.mask, true
+	}
+	redacted := make([]interface{}, value.Len())
+	changed := false
+	for i := range redacted {
+		var redactedElement bool
+		redacted[i], redactedElement = r.redactValue(value.Index(i).Interface(), depth+1)
+		changed = changed || redactedElement
+	}
+	if !changed {
+		return value.Interface(), false
+	}
+	return redacted, true
+}
+
+// Transform redacts the message, fields and errors of the entry. Field values of denied keys are replaced by the
+// mask, other values are replaced by their redacted text or a redacted copy when holding sensitive data (see
+// redactValue).
+func (r *Redactor) Transform(entry *LogEntry) *LogEntry {
+	if r == nil {
+		return entry
+	}
+	entry.Message = r.Redact(entry.Message)
+	if len(entry.Fields) > 0 {
+		fields := make([]Field, len(entry.Fields))
+		for i, field := range entry.Fields {
+			if r.denied[strings.ToLower(field.Key)] {
+				r.count(DeniedFieldCount, 1)
+				field.Value = r.mask
+			} else {
+				field.Value, _ = r.redactValue(field.Value, 0)
+			}
+			fields[i] = field
+		}
+		entry.Fields = fields
+	}
+	entry.Errors = r.redactErrors(entry.Errors)
+	return entry
+}
@@ -0,0 +1,323 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry holds a set of loggers indexed by their names, including its own DEFAULT logger. The package functions
+// operate on a default registry, separate registries allow independent logger trees (tenants, parallel tests...).
+type Registry struct {
+	loggers          map[string]Logger      // map of all existing loggers. Indexed by their names.
+	lock             sync.Mutex             // mutex to manipulate the loggers map and replace the DEFAULT logger
+	defaultLogger    atomic.Pointer[Logger] // the registry's DEFAULT logger, read without locking
+	defaultCallDepth int                    // call depth of the DEFAULT logger, deeper for the package functions' wrapping
+}
+
+// NewRegistry creates a new registry holding only a DEFAULT logger with the default options
+func NewRegistry() *Registry {
+	return newRegistry(3)
+}
+
+// newRegistry creates a registry with a default logger with the given call depth
+func newRegistry(defaultCallDepth int) *Registry {
+	registry := &Registry{
+		loggers:          make(map[string]Logger),
+		defaultCallDepth: defaultCallDepth,
+	}
+	logger, _ := registry.getWithOptions(DEFAULT, Standard(), false)
+	registry.defaultLogger.Store(&logger)
+	return registry
+}
+
+// Get will create or get an existing logger with the given name. If the logger doesn't exist it will be created with
+// the default options (warning level, logs to stdout and non-failing criticals), otherwise it's returned whatever
+// options it was created with. The name must be a non-empty string (may be spaces).
+func (r *Registry) Get(name string) (Logger, error) {
+	logger, e := r.GetWithOptions(name, Standard())
+	var returnError error
+	if e != nil && !errors.Is(e, ErrReinitializingExistingLogger) {
+		returnError = e
+	}
+	return logger, returnError
+}
+
+// GetWithOptions will create a log with the provided options if it doesn't exist yet or returns an existing log if
+// the provided options are the same as the options the existing logger is configured with. Trying to get an existing
+// logger with different options returns the existing logger with an error wrapping ErrReinitializingExistingLogger
+// and reporting the differing option. The name logger may not be an empty string (can be filled spaces).
+func (r *Registry) GetWithOptions(name string, options Options) (Logger, error) {
+	if len(name) == 0 {
+		return nil, ErrEmptyLoggerName
+	}
+	return r.getWithOptions(name, options, false)
+}
+
+// GetOrReconfigure will create a log with the provided options if it doesn't exist yet or returns the existing log
+// after applying the options to it in place if they differ from the options it's configured with, including the
+// starting level.
+func (r *Registry) GetOrReconfigure(name string, options Options) (Logger, error) {
+	if len(name) == 0 {
+		return nil, ErrEmptyLoggerName
+	}
+	return r.getWithOptions(name, options, true)
+}
+
+// private getWithOptions function that actually fetches or creates the logger. The internal version allows an empty
+// string as a name allowing the creation of the DEFAULT logger.
+func (r *Registry) getWithOptions(name string, o Options, reconfigure bool) (Logger, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if o == nil {
+		o = Standard()
+	}
+	if logger, found := r.loggers[name]; !found {
+		if logger, e := r.newLogger(name, o.(*options)); e != nil {
+			return nil, e
+		} else {
+			r.loggers[name] = logger
+			return logger, nil
+		}
+	} else {
+		if difference := logger.(managedLogger).loggerOptions().difference(o.(*options)); difference != "" {
+			if !reconfigure {
+				return logger, reinitializingError(difference)
+			}
+			return logger, reconfigureLogger(logger, o.(*options))
+		}
+		return logger, nil
+	}
+}
+
+// Reconfigure applies the options to the existing logger with the given name in place, including the starting
+// level, while other goroutines may keep logging through it. The options must be for the same type of logger.
+func (r *Registry) Reconfigure(name string, o Options) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	logger, found := r.loggers[name]
+	if !found {
+		return ErrLoggerDoesNotExist
+	}
+	if o == nil {
+		o = Standard()
+	}
+	return reconfigureLogger(logger, o.(*options))
+}
+
+// reinitializingError wraps ErrReinitializingExistingLogger reporting the differing option
+func reinitializingError(difference string) error {
+	return fmt.Errorf("%w: different %s", ErrReinitializingExistingLogger, difference)
+}
+
+// reconfigureLogger applies the options to the logger if they're for the same type of logger
+func reconfigureLogger(logger Logger, o *options) error {
+	managed := logger.(managedLogger)
+	if managed.loggerOptions().loggerType != o.loggerType {
+		return reinitializingError("logger type")
+	}
+	managed.reconfigure(o)
+	return nil
+}
+
+// newLogger creates a new logger for the registry, adjusting the call depth of the DEFAULT logger
+func (r *Registry) newLogger(name string, o *options) (Logger, error) {
+	logger, e := newLogger(name, o)
+	if e == nil && name == DEFAULT {
+		logger.(managedLogger).setCallDepth(r.defaultCallDepth)
+	}
+	return logger, e
+}
+
+// Default returns the registry's DEFAULT logger
+func (r *Registry) Default() Logger {
+	return *r.defaultLogger.Load()
+}
+
+// SetDefaultLogger allows overriding the registry's default logger with different options
+func (r *Registry) SetDefaultLogger(o Options) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if logger, e := r.newLogger(DEFAULT, o.(*options)); e != nil {
+		return e
+	} else {
+		r.defaultLogger.Store(&logger)
+		r.loggers[DEFAULT] = logger
+	}
+
+	return nil
+}
+
+// setDefaultLogger replaces the registry's default logger with the given logger instance, returning the replaced one
+func (r *Registry) setDefaultLogger(logger Logger) Logger {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	previous := r.Default()
+	r.defaultLogger.Store(&logger)
+	r.loggers[DEFAULT] = logger
+	return previous
+}
+
+// DefaultLoggerOptions returns a copy of the options the registry's current default logger is configured with
+func (r *Registry) DefaultLoggerOptions() Options {
+	return r.Default().(managedLogger).loggerOptions().clone()
+}
+
+// SetLevel sets the log level of the registry's default logger
+func (r *Registry) SetLevel(level int) {
+	r.Default().SetLevel(level)
+}
+
+// Level returns the current log level of the registry's default logger
+func (r *Registry) Level() int {
+	return r.Default().Level()
+}
+
+// SetLoggerLevel sets the log level of a logger by name. DEFAULT may be used to set the default logger level.
+func (r *Registry) SetLoggerLevel(name string, level int) error {
+	r.lock.Lock()
+	logger, found := r.loggers[name]
+	r.lock.Unlock()
+
+	if !found {
+		return ErrLoggerDoesNotExist
+	}
+
+	logger.SetLevel(level)
+	return nil
+}
+
+// SetLoggerLevels sets the log levels of several loggers at once. If any logger is not found it will be omitted from the response
+func (r *Registry) SetLoggerLevels(loggerLevels map[string]int) map[string]int {
+	result := make(map[string]int)
+
+	for k, l := range loggerLevels {
+		if e := r.SetLoggerLevel(k, l); e == nil {
+			result[k] = l
+		}
+	}
+
+	return result
+}
+
+// PushLevel temporarily sets the level of the logger with the given name until the returned function is called,
+// restoring its previous level. Returns ErrLoggerDoesNotExist if the logger doesn't exist, along with a function doing
+// nothing.
+func (r *Registry) PushLevel(name string, level int) (func(), error) {
+	r.lock.Lock()
+	logger, found := r.loggers[name]
+	r.lock.Unlock()
+
+	if !found {
+		return func() {}, ErrLoggerDoesNotExist
+	}
+	return logger.PushLevel(level), nil
+}
+
+// OverrideLevel temporarily sets the level of the logger with the given name for the given duration, or until the
+// returned function is called. Returns ErrLoggerDoesNotExist if the logger doesn't exist, along with a function doing
+// nothing.
+func (r *Registry) OverrideLevel(name string, level int, duration time.Duration) (func(), error) {
+	r.lock.Lock()
+	logger, found := r.loggers[name]
+	r.lock.Unlock()
+
+	if !found {
+		return func() {}, ErrLoggerDoesNotExist
+	}
+	return logger.OverrideLevel(level, duration), nil
+}
+
+// LoggerLevels gets the current log levels of all known loggers (see LoggerVerbosities for their verbosity)
+func (r *Registry) LoggerLevels() map[string]int {
+	loggerLevels := make(map[string]int)
+	r.lock.Lock()
+	for k, l := range r.loggers {
+		loggerLevels[k] = l.Level()
+	}
+	r.lock.Unlock()
+	return loggerLevels
+}
+
+// SetLoggerVerbosity sets the verbosity of a logger by name
+func (r *Registry) SetLoggerVerbosity(name string, verbosity int) error {
+	r.lock.Lock()
+	logger, found := r.loggers[name]
+	r.lock.Unlock()
+
+	if !found {
+		return ErrLoggerDoesNotExist
+	}
+
+	logger.SetVerbosity(verbosity)
+	return nil
+}
+
+// SetLoggerVModule sets the verbosity of the source files matching patterns of a logger by name, as a comma separated
+// list of pattern=verbosity pairs (like "db*=2,controllers/*=4")
+func (r *Registry) SetLoggerVModule(name string, spec string) error {
+	r.lock.Lock()
+	logger, found := r.loggers[name]
+	r.lock.Unlock()
+
+	if !found {
+		return ErrLoggerDoesNotExist
+	}
+
+	return logger.SetVModule(spec)
+}
+
+// LoggerVerbosities gets the current verbosity of all known loggers
+func (r *Registry) LoggerVerbosities() map[string]int {
+	verbosities := make(map[string]int)
+	r.lock.Lock()
+	for k, l := range r.loggers {
+		verbosities[k] = l.Verbosity()
+	}
+	r.lock.Unlock()
+	return verbosities
+}
+
+// LoggerLevel gets the current log level of the logger with the given name. ErrLoggerDoesNotExist is returned as an
+// error if a logger with the given name doesn't is unknown.
+func (r *Registry) LoggerLevel(name string) (int, error) {
+	r.lock.Lock()
+	logger, found := r.loggers[name]
+	r.lock.Unlock()
+
+	if !found {
+		return 0, ErrLoggerDoesNotExist
+	}
+
+	return logger.Level(), nil
+}
+
+// LoggerLevelName gets the current log level name of the logger with the given name. ErrLoggerDoesNotExist is returned as an
+// error if a logger with the given name doesn't is unknown. Utility method for loggers using the standard severity scale.
+func (r *Registry) LoggerLevelName(name string) (string, error) {
+	if level, e := r.LoggerLevel(name); e == nil {
+		return LevelName(level), nil
+	} else {
+		return "", e
+	}
+}
+
+// LoggerLevelNames gets the current log level names of all known loggers. Only relevant if logger is using the standard severity scale.
+func (r *Registry) LoggerLevelNames() map[string]string {
+	loggerLevels := r.LoggerLevels()
+	loggerLevelNames := make(map[string]string)
+	for k, l := range loggerLevels {
+		loggerLevelNames[k] = LevelName(l)
+	}
+	return loggerLevelNames
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	resetLoggers()
+	registry := NewRegistry()
+
+	t.Run("Test registries are isolated", func(t *testing.T) {
+		logger, e := registry.Get("Isolated")
+		if e != nil {
+			t.Error("Getting log from registry should not raise an error :", e)
+		}
+		if _, e = LoggerLevel("Isolated"); e != ErrLoggerDoesNotExist {
+			t.Error("Registry logger should not be in the default registry.")
+		}
+		if packageLogger, _ := Get("Isolated"); packageLogger == logger {
+			t.Error("Registries should not share loggers.")
+		}
+		if same, _ := registry.Get("Isolated"); same != logger {
+			t.Error("Registry not returning the existing logger.")
+		}
+	})
+
+	t.Run("Test registry levels", func(t *testing.T) {
+		if e := registry.SetLoggerLevel("Isolated", DEBUG); e != nil {
+			t.Error("Setting the level of an existing logger failed :", e)
+		}
+		registry.SetLevel(ERROR)
+		levels := registry.LoggerLevelNames()
+		if len(levels) != 2 || levels["Isolated"] != "DEBUG" || levels[DEFAULT] != "ERROR" {
+			t.Error("Unexpected registry levels :", levels)
+		}
+		if Level() == ERROR {
+			t.Error("Registry default logger level leaked into the package default logger.")
+		}
+	})
+
+	t.Run("Test registry default logger", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		if e := registry.SetDefaultLogger(Standard().WithWriter(output).WithLogPrefix(Source)); e != nil {
+			t.Error("Setting the registry default logger failed :", e)
+		}
+		registry.Default().Warning("registry default")
+		if !strings.HasPrefix(output.String(), "registry_test.go:") || buf.Len() > 0 {
+			t.Error("Unexpected registry default logger output :", output.String())
+		}
+	})
+
+	t.Run("Test replacing the default logger while logging", func(t *testing.T) {
+		output := &lockedBuffer{}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				registry.Default().Warning("concurrent")
+			}
+		}()
+		for i := 0; i < 10; i++ {
+			_ = registry.SetDefaultLogger(Standard().WithWriter(output))
+		}
+		<-done
+		if logger, _ := registry.Get(DEFAULT); logger != registry.Default() {
+			t.Error("Default logger not the registered DEFAULT logger.")
+		}
+	})
+}
+
+func TestOptionsDifference(t *testing.T) {
+	writer := &bytes.Buffer{}
+	appender := &lockedAppender{}
+	sampler := FirstThenEvery(1, 2, time.Second)
+	match := LevelRange(CRITICAL, ERROR)
+	full := func() Options {
+		return SyncedAppenders().WithAppenders(appender).WithLevelLogPrefix(INFO, Name).WithSampler(sampler).
+			WithRoutes(Route{Match: match, Writer: writer})
+	}
+	for _, tc := range []struct {
+		options    Options
+		difference string
+	}{
+		{full(), ""},
+		{Standard(), "logger type"},
+		{full().WithFailingCriticals(), "failing criticals"},
+		{full().WithStartingLevel(INFO), "starting level"},
+		{full().WithLevelLogPrefix(INFO, Name, Separator), "level formats"},
+		{SyncedAppenders().WithAppenders(&lockedAppender{}).WithLevelLogPrefix(INFO, Name).WithSampler(sampler), "appenders"},
+		{full().WithSampler(FirstThenEvery(1, 2, time.Second)), "samplers"},
+		{full().WithDeduplication(time.Second), "deduplication window"},
+		{full().WithRoutes(Route{Match: match}), "routes"},
+		{full().WithStackTrace(ERROR), "stack trace level"},
+	} {
+		if difference := full().(*options).difference(tc.options.(*options)); difference != tc.difference {
+			t.Errorf("Unexpected options difference (expected %q, got %q)", tc.difference, difference)
+		}
+	}
+	if sameValue(match, LoggerNamePattern("*")) || !sameValue(match, match) {
+		t.Error("Functions not compared by identity.")
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	registry := NewRegistry()
+	first, second := &lockedBuffer{}, &lockedBuffer{}
+	logger, _ := registry.GetWithOptions("Reconfigured", Standard().WithWriter(first))
+
+	t.Run("Test swapping settings while logging", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				logger.Warning("concurrent")
+			}
+		}()
+		if e := logger.SetWriter(second); e != nil {
+			t.Error("Swapping the writer of a standard logger failed :", e)
+		}
+		logger.SetLevelLogPrefix(WARNING, LogLevel, Source)
+		logger.SetFailingCriticals(true)
+		<-done
+		if e := logger.SetAppenders(&lockedAppender{}); e != ErrUnsupportedByLoggerType {
+			t.Error("Swapping the appenders of a standard logger should fail :", e)
+		}
+
+		logger.Warning("reconfigured")
+		lines := strings.Split(strings.TrimSpace(second.String()), "\n")
+		if !strings.HasPrefix(lines[len(lines)-1], "[WRN] registry_test.go:") {
+			t.Error("Unexpected output after swapping the prefix :", lines[len(lines)-1])
+		}
+		if strings.Count(first.String()+second.String(), "concurrent") != 100 {
+			t.Error("Entries lost while swapping settings.")
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Swapped critical policy not applied.")
+				}
+			}()
+			logger.Critical("failing")
+		}()
+		if d := registry.Loggers()[1]; d.Writer != second || len(d.LevelFormats[WARNING]) != 2 {
+			t.Error("Descriptor not reflecting the swapped settings :", d)
+		}
+	})
+
+	t.Run("Test reconfiguring by name", func(t *testing.T) {
+		if e := registry.Reconfigure("Reconfigured", Standard().WithWriter(first).WithStartingLevel(INFO)); e != nil {
+			t.Error("Reconfiguring an existing logger failed :", e)
+		}
+		if logger.Level() != INFO {
+			t.Error("Reconfigured starting level not applied :", logger.Level())
+		}
+		if e := registry.Reconfigure("Missing", Standard()); e != ErrLoggerDoesNotExist {
+			t.Error("Reconfiguring a missing logger should fail :", e)
+		}
+		if e := registry.Reconfigure("Reconfigured", SyncedAppenders()); !errors.Is(e, ErrReinitializingExistingLogger) {
+			t.Error("Reconfiguring a logger with another logger type should fail :", e)
+		}
+	})
+
+	t.Run("Test changing options after use", func(t *testing.T) {
+		o := Standard().WithWriter(first)
+		copied, _ := registry.GetWithOptions("Copied", o)
+		o.WithFailingCriticals().WithLogPrefix(Name)
+		if _, e := registry.GetWithOptions("Copied", Standard().WithWriter(first)); e != nil {
+			t.Error("Logger options changed with the options it was created with :", e)
+		}
+		_ = registry.Reconfigure("Copied", o)
+		o.WithLogPrefix(LogLevel)
+		if current := copied.(managedLogger).loggerOptions(); current.levelFormats[WARNING][0] != Name {
+			t.Error("Logger options changed with the options it was reconfigured with.")
+		}
+		registry.DefaultLoggerOptions().WithFailingCriticals()
+		if registry.DefaultLoggerOptions().(*options).failingCriticals {
+			t.Error("Default logger options changed through the returned options.")
+		}
+	})
+
+	t.Run("Test keeping sampling and deduplication state", func(t *testing.T) {
+		before, after := &lockedAppender{}, &lockedAppender{}
+		sampled, _ := registry.GetWithOptions("Sampled", SyncedAppenders().WithAppenders(before).
+			WithLevelSampler(ERROR, FirstThenEvery(1, 0, time.Hour)).WithSummaryInterval(time.Hour).WithDeduplication(time.Hour))
+		sampled.Error("sampled")
+		sampled.Error("sampled")
+		sampled.Warning("repeated")
+		sampled.Warning("repeated")
+		_ = sampled.SetAppenders(after)
+		sampled.Error("sampled")
+		if len(before.messages()) != 2 || len(after.messages()) != 0 {
+			t.Fatal("Held back entries emitted when swapping the appenders :", before.messages(), after.messages())
+		}
+
+		sampled.(managedLogger).flush()
+		if messages := after.messages(); len(messages) != 2 || messages[0] != "suppressed 2 messages" || messages[1] != "repeated\n" {
+			t.Error("Sampling and deduplication state not kept when swapping the appenders :", messages)
+		}
+	})
+
+	t.Run("Test swapping appenders", func(t *testing.T) {
+		before, after := &lockedAppender{}, &lockedAppender{}
+		synced, _ := registry.GetWithOptions("Synced", SyncedAppenders().WithAppenders(before))
+		_ = synced.SetAppenders(after)
+		synced.Warning("swapped")
+		if len(before.messages()) != 0 || len(after.messages()) != 1 {
+			t.Error("Entries not handed to the swapped appenders.")
+		}
+		if e := synced.SetWriter(first); e != ErrUnsupportedByLoggerType {
+			t.Error("Swapping the writer of a synced appenders logger should fail :", e)
+		}
+	})
+}
@@ -0,0 +1,337 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRingBufferEntries is the default number of entries kept per logger by a ring buffer
+const DefaultRingBufferEntries = 1000
+
+// subscriberBuffer is the number of entries buffered for a ring buffer subscriber before entries are dropped
+const subscriberBuffer = 64
+
+// RingBufferConfig holds the configuration of a ring buffer appender
+type RingBufferConfig struct {
+	MaxEntries int // maximum number of entries kept per logger (defaults to DefaultRingBufferEntries if MaxBytes is not set)
+	MaxBytes   int // maximum size of the messages and fields of the entries kept per logger (unbounded if not set)
+}
+
+// ring holds the last entries of a logger in a circular array, the oldest one at head
+type ring struct {
+	entries []*LogEntry
+	sizes   []int
+	head    int
+	count   int
+	bytes   int
+}
+
+// newRing creates a ring, with its circular array allocated upfront if limited to a number of entries
+func newRing(maxEntries int) *ring {
+	if maxEntries > 0 {
+		return &ring{entries: make([]*LogEntry, maxEntries), sizes: make([]int, maxEntries)}
+	}
+	return &ring{}
+}
+
+// at returns the ith oldest entry of the ring
+func (r *ring) at(i int) *LogEntry {
+	return r.entries[(r.head+i)%len(r.entries)]
+}
+
+// push adds an entry to the ring, growing its circular array if full. Only rings not limited by a number of entries
+// grow, limited ones are allocated with their maximum size.
+func (r *ring) push(entry *LogEntry, size int) {
+	if r.count == len(r.entries) {
+		capacity := 2 * len(r.entries)
+		if capacity == 0 {
+			capacity = 16
+		}
+		entries, sizes := make([]*LogEntry, capacity), make([]int, capacity)
+		for i := 0; i < r.count; i++ {
+			entries[i] = r.at(i)
+			sizes[i] = r.sizes[(r.head+i)%len(r.sizes)]
+		}
+		r.entries, r.sizes, r.head = entries, sizes, 0
+	}
+	tail := (r.head + r.count) % len(r.entries)
+	r.entries[tail] = entry
+	r.sizes[tail] = size
+	r.count++
+	r.bytes += size
+}
+
+// pop evicts the oldest entry of the ring
+func (r *ring) pop() {
+	r.bytes -= r.sizes[r.head]
+	r.entries[r.head] = nil
+	r.head = (r.head + 1) % len(r.entries)
+	r.count--
+}
+
+// copyEntry copies an entry along with its stack, fields and errors, so that it's not affected by changes to the
+// original entry and vice versa
+func copyEntry(entry *LogEntry) *LogEntry {
+	copied := *entry
+	copied.Stack = append([]StackFrame(nil), entry.Stack...)
+	copied.Fields = append([]Field(nil), entry.Fields...)
+	copied.Errors = append([]ErrorInfo(nil), entry.Errors...)
+	return &copied
+}
+
+// RingBuffer appender keeping copies of the last entries of each logger in memory, to be queried or served through
+// http. Queries and subscribers get their own copies of the entries.
+type RingBuffer struct {
+	config      RingBufferConfig
+	rings       map[string]*ring
+	subscribers map[chan *LogEntry]struct{}
+	mutex       sync.RWMutex
+}
+
+// NewRingBuffer creates a ring buffer appender
+func NewRingBuffer(config RingBufferConfig) *RingBuffer {
+	if config.MaxEntries <= 0 && config.MaxBytes <= 0 {
+		config.MaxEntries = DefaultRingBufferEntries
+	}
+	return &RingBuffer{
+		config:      config,
+		rings:       make(map[string]*ring),
+		subscribers: make(map[chan *LogEntry]struct{}),
+	}
+}
+
+// entrySize estimates the memory held by an entry's message and fields
+func entrySize(entry *LogEntry) int {
+	size := len(entry.Message)
+	for _, field := range entry.Fields {
+		size += len(field.Key) + len(fmt.Sprint(field.Value))
+	}
+	return size
+}
+
+// Print keeps a copy of the entry in its logger's ring, evicting the oldest entries over the limits, and sends it to
+// the subscribers
+func (rb *RingBuffer) Print(entry *LogEntry) {
+	size := entrySize(entry)
+	entry = copyEntry(entry)
+
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	r, found := rb.rings[entry.Logger]
+	if !found {
+		r = newRing(rb.config.MaxEntries)
+		rb.rings[entry.Logger] = r
+	}
+	if rb.config.MaxEntries > 0 && r.count == rb.config.MaxEntries {
+		r.pop()
+	}
+	r.push(entry, size)
+	for r.count > 0 && rb.config.MaxBytes > 0 && r.bytes > rb.config.MaxBytes {
+		r.pop()
+	}
+
+	for subscriber := range rb.subscribers {
+		select {
+		case subscriber <- copyEntry(entry):
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving new entries and a function to cancel the subscription. Entries are dropped
+// for subscribers not keeping up.
+func (rb *RingBuffer) Subscribe() (<-chan *LogEntry, func()) {
+	subscriber := make(chan *LogEntry, subscriberBuffer)
+	rb.mutex.Lock()
+	rb.subscribers[subscriber] = struct{}{}
+	rb.mutex.Unlock()
+	return subscriber, func() {
+		rb.mutex.Lock()
+		delete(rb.subscribers, subscriber)
+		rb.mutex.Unlock()
+	}
+}
+
+// Query creates a query on the entries of the ring buffer, matching all entries until narrowed
+func (rb *RingBuffer) Query() *Query {
+	return &Query{ringBuffer: rb, level: int(^uint(0) >> 1)}
+}
+
+// Query selects entries of a ring buffer
+type Query struct {
+	ringBuffer *RingBuffer
+	level      int
+	since      time.Time
+	until      time.Time
+	logger     string
+	contains   string
+	limit      int
+}
+
+// Level narrows the query to entries at the level or more severe
+func (q *Query) Level(level int) *Query {
+	q.level = level
+	return q
+}
+
+// Since narrows the query to entries logged at or after the time
+func (q *Query) Since(since time.Time) *Query {
+	q.since = since
+	return q
+}
+
+// Until narrows the query to entries logged before the time
+func (q *Query) Until(until time.Time) *Query {
+	q.until = until
+	return q
+}
+
+// Logger narrows the query to entries of the logger with the given name
+func (q *Query) Logger(name string) *Query {
+	q.logger = name
+	return q
+}
+
+// Contains narrows the query to entries with messages containing the text
+func (q *Query) Contains(text string) *Query {
+	q.contains = text
+	return q
+}
+
+// Limit narrows the query to the most recent matching entries
+func (q *Query) Limit(limit int) *Query {
+	q.limit = limit
+	return q
+}
+
+// matches checks if an entry matches the query, disregarding its logger
+func (q *Query) matches(entry *LogEntry) bool {
+	return entry.Level <= q.level &&
+		(q.since.IsZero() || !entry.Timestamp.Before(q.since)) &&
+		(q.until.IsZero() || entry.Timestamp.Before(q.until)) &&
+		(len(q.contains) == 0 || strings.Contains(entry.Message, q.contains))
+}
+
+// Entries returns copies of the entries matching the query, ordered by time
+func (q *Query) Entries() []*LogEntry {
+	var entries []*LogEntry
+	q.ringBuffer.mutex.RLock()
+	for name, r := range q.ringBuffer.rings {
+		if len(q.logger) > 0 && name != q.logger {
+			continue
+		}
+		for i := 0; i < r.count; i++ {
+			if entry := r.at(i); q.matches(entry) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	q.ringBuffer.mutex.RUnlock()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	if q.limit > 0 && len(entries) > q.limit {
+		entries = entries[len(entries)-q.limit:]
+	}
+	for i, entry := range entries {
+		entries[i] = copyEntry(entry)
+	}
+	return entries
+}
+
+// parseQuery builds a query from the request parameters: level (name or number), since and until (RFC 3339),
+// logger, contains and limit
+func (rb *RingBuffer) parseQuery(r *http.Request) (*Query, error) {
+	q := rb.Query()
+	parameters := r.URL.Query()
+	if level := parameters.Get("level"); len(level) > 0 {
+		if severity := LevelSeverity(strings.ToUpper(level)); severity != UNKNOWN {
+			q.Level(severity)
+		} else if severity, e := strconv.Atoi(level); e == nil {
+			q.Level(severity)
+		} else {
+			return nil, e
+		}
+	}
+	for name, setter := range map[string]func(time.Time) *Query{"since": q.Since, "until": q.Until} {
+		if value := parameters.Get(name); len(value) > 0 {
+			t, e := time.Parse(time.RFC3339, value)
+			if e != nil {
+				return nil, e
+			}
+			setter(t)
+		}
+	}
+	if limit := parameters.Get("limit"); len(limit) > 0 {
+		n, e := strconv.Atoi(limit)
+		if e != nil {
+			return nil, e
+		}
+		q.Limit(n)
+	}
+	return q.Logger(parameters.Get("logger")).Contains(parameters.Get("contains")), nil
+}
+
+// ServeHTTP serves the entries matching the request's query parameters as a JSON array. Requests accepting
+// text/event-stream (or with a stream parameter) are instead streamed the new matching entries as server-sent events.
+func (rb *RingBuffer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q, e := rb.parseQuery(r)
+	if e != nil {
+		http.Error(w, e.Error(), http.StatusBadRequest)
+		return
+	}
+
+	encoder := JSONEncoder{}
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") && !r.URL.Query().Has("stream") {
+		body := []byte{'['}
+		for i, entry := range q.Entries() {
+			if i > 0 {
+				body = append(body, ',')
+			}
+			body = encoder.Encode(body, entry)
+			body = body[:len(body)-1]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(append(body, ']'))
+		return
+	}
+
+	flusher, canFlush := w.(http.Flusher)
+	if !canFlush {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	entries, cancel := rb.Subscribe()
+	defer cancel()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	var event []byte
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case entry := <-entries:
+			if !q.matches(entry) || (len(q.logger) > 0 && entry.Logger != q.logger) {
+				continue
+			}
+			event = append(event[:0], "data: "...)
+			event = encoder.Encode(event, entry)
+			if _, e := w.Write(append(event, '\n')); e != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer(t *testing.T) {
+	start := time.Date(2021, 6, 25, 10, 0, 0, 0, time.UTC)
+	entry := func(logger string, level int, minute int, message string) *LogEntry {
+		return &LogEntry{Logger: logger, Level: level, Timestamp: start.Add(time.Duration(minute) * time.Minute), Message: message}
+	}
+
+	t.Run("Test eviction", func(t *testing.T) {
+		rb := NewRingBuffer(RingBufferConfig{MaxEntries: 2})
+		for i, message := range []string{"first", "second", "third"} {
+			rb.Print(entry("A", INFO, i, message))
+		}
+		rb.Print(entry("B", INFO, 3, "other"))
+		if entries := rb.Query().Logger("A").Entries(); len(entries) != 2 || entries[0].Message != "second" {
+			t.Error("Unexpected entries after eviction by count :", entries)
+		}
+
+		rb = NewRingBuffer(RingBufferConfig{MaxBytes: 10})
+		rb.Print(entry("A", INFO, 0, "12345"))
+		rb.Print(entry("A", INFO, 1, "1234"))
+		withField := entry("A", INFO, 2, "12")
+		withField.Fields = []Field{F("k", 1)}
+		rb.Print(withField)
+		if entries := rb.Query().Entries(); len(entries) != 2 || entries[0].Message != "1234" {
+			t.Error("Unexpected entries after eviction by size :", entries)
+		}
+
+		rb = NewRingBuffer(RingBufferConfig{MaxEntries: 3})
+		for i := 0; i < 10; i++ {
+			rb.Print(entry("A", INFO, i, strconv.Itoa(i)))
+		}
+		if entries := rb.Query().Entries(); len(entries) != 3 || entries[0].Message != "7" || entries[2].Message != "9" {
+			t.Error("Unexpected entries after wrapping around :", entries)
+		}
+
+		rb = NewRingBuffer(RingBufferConfig{MaxBytes: 30})
+		for i := 0; i < 40; i++ {
+			rb.Print(entry("A", INFO, i, "x"))
+		}
+		if entries := rb.Query().Entries(); len(entries) != 30 || entries[0].Timestamp != start.Add(10*time.Minute) {
+			t.Error("Unexpected entries after growing :", len(entries))
+		}
+	})
+
+	t.Run("Test entries are copied", func(t *testing.T) {
+		rb := NewRingBuffer(RingBufferConfig{})
+		original := entry("A", INFO, 0, "original")
+		original.Fields = []Field{F("k", 1)}
+		rb.Print(original)
+		original.Message = "changed"
+		original.Fields[0] = F("k", 2)
+
+		queried := rb.Query().Entries()[0]
+		if queried.Message != "original" || queried.Fields[0] != F("k", 1) {
+			t.Error("Stored entry changed with the printed one :", queried)
+		}
+		queried.Fields[0] = F("k", 3)
+		if entries := rb.Query().Entries(); entries[0].Fields[0] != F("k", 1) {
+			t.Error("Stored entry changed with the queried one :", entries[0])
+		}
+	})
+
+	rb := NewRingBuffer(RingBufferConfig{})
+	rb.Print(entry("db", ERROR, 0, "connection lost"))
+	rb.Print(entry("api", INFO, 1, "request served"))
+	rb.Print(entry("db", DEBUG, 2, "connection restored"))
+	rb.Print(entry("api", WARNING, 3, "slow request"))
+
+	t.Run("Test queries", func(t *testing.T) {
+		if entries := rb.Query().Entries(); len(entries) != 4 || entries[1].Message != "request served" {
+			t.Error("Entries not ordered by time :", entries)
+		}
+		if entries := rb.Query().Level(WARNING).Entries(); len(entries) != 2 {
+			t.Error("Unexpected entries by level :", entries)
+		}
+		if entries := rb.Query().Since(start.Add(time.Minute)).Until(start.Add(3 * time.Minute)).Entries(); len(entries) != 2 {
+			t.Error("Unexpected entries by time range :", entries)
+		}
+		if entries := rb.Query().Logger("db").Contains("connection").Limit(1).Entries(); len(entries) != 1 || entries[0].Message != "connection restored" {
+			t.Error("Unexpected entries by logger, text and limit :", entries)
+		}
+	})
+
+	server := httptest.NewServer(rb)
+	defer server.Close()
+
+	t.Run("Test serving entries", func(t *testing.T) {
+		response, e := http.Get(server.URL + "?level=warning&logger=api")
+		if e != nil {
+			t.Fatal("Failed to query entries :", e)
+		}
+		defer response.Body.Close()
+		var entries []map[string]interface{}
+		if e := json.NewDecoder(response.Body).Decode(&entries); e != nil || len(entries) != 1 || entries[0]["message"] != "slow request" {
+			t.Errorf("Unexpected served entries %v (error %v)", entries, e)
+		}
+
+		if response, e := http.Get(server.URL + "?since=yesterday"); e != nil || response.StatusCode != http.StatusBadRequest {
+			t.Error("Invalid query not rejected")
+		}
+	})
+
+	t.Run("Test streaming entries", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?level=ERROR", nil)
+		request.Header.Set("Accept", "text/event-stream")
+		response, e := http.DefaultClient.Do(request)
+		if e != nil {
+			t.Fatal("Failed to stream entries :", e)
+		}
+		defer response.Body.Close()
+
+		rb.Print(entry("api", INFO, 4, "filtered out"))
+		rb.Print(entry("api", ERROR, 5, "request failed"))
+		line, e := bufio.NewReader(response.Body).ReadString('\n')
+		if e != nil || !strings.HasPrefix(line, "data: {") || !strings.Contains(line, `"message":"request failed"`) {
+			t.Errorf("Unexpected event %q (error %v)", line, e)
+		}
+	})
+}
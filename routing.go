@@ -0,0 +1,62 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"io"
+	"path"
+)
+
+// Route sends the entries it matches to its own writer and appenders. When a logger has routes, entries are output
+// through every matching route, and only entries matching no route are output through the logger's own writer or
+// appenders.
+type Route struct {
+	Match     func(entry *LogEntry) bool // predicate selecting the entries of the route (nil matches all entries)
+	Writer    io.Writer                  // writer the entries are written to (optional)
+	Encoder   Encoder                    // format of the entries written to the writer (defaults to the logger's text format)
+	Appenders []Appender                 // appenders the entries are handed to (optional)
+}
+
+// LevelRange creates a route predicate matching entries with levels from mostSevere to leastSevere (inclusive)
+func LevelRange(mostSevere int, leastSevere int) func(entry *LogEntry) bool {
+	return func(entry *LogEntry) bool {
+		return entry.Level >= mostSevere && entry.Level <= leastSevere
+	}
+}
+
+// LoggerNamePattern creates a route predicate matching entries of loggers with names matching the pattern, using
+// the syntax of path.Match (like "db*")
+func LoggerNamePattern(pattern string) func(entry *LogEntry) bool {
+	return func(entry *LogEntry) bool {
+		matched, _ := path.Match(pattern, entry.Logger)
+		return matched
+	}
+}
+
+// route outputs the entry through all matching routes. The format function is used to write entries to route
+// writers without an encoder. Returns false if no route matched the entry.
+func route(routes []Route, entry *LogEntry, buf *[]byte, format func(buf *[]byte, entry *LogEntry)) bool {
+	matched := false
+	for i := range routes {
+		r := &routes[i]
+		if r.Match != nil && !r.Match(entry) {
+			continue
+		}
+		matched = true
+		if r.Writer != nil {
+			*buf = (*buf)[:0]
+			if r.Encoder != nil {
+				*buf = r.Encoder.Encode(*buf, entry)
+			} else {
+				format(buf, entry)
+			}
+			_, _ = r.Writer.Write(*buf)
+		}
+		for _, appender := range r.Appenders {
+			appender.Print(entry)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRouting(t *testing.T) {
+	t.Run("Test routing standard logger by level", func(t *testing.T) {
+		stderr, stdout, fallback := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+		alerts := &recordingAppender{}
+		logger, _ := newLogger("ROUTED", Standard().WithWriter(fallback).WithStartingLevel(TRACE).WithLogPrefix(LogLevel).WithRoutes(
+			Route{Match: LevelRange(CRITICAL, ERROR), Writer: stderr, Encoder: TextEncoder{Prefix: []uint{Name, Separator}}, Appenders: []Appender{alerts}},
+			Route{Match: LevelRange(INFO, TRACE), Writer: stdout},
+		).(*options))
+
+		logger.Critical("CRT")
+		logger.Error("ERR")
+		logger.Warning("WRN")
+		logger.Info("INF")
+		logger.Debug("DBG")
+
+		if stderr.String() != "ROUTED - CRT\nROUTED - ERR\n" {
+			t.Errorf("Unexpected stderr route output: %q", stderr.String())
+		}
+		if len(alerts.entries) != 2 || alerts.entries[0].Function != "TestRouting.func1" {
+			t.Error("Unexpected alert route entries :", alerts.entries)
+		}
+		if stdout.String() != "[INF] INF\n[DBG] DBG\n" {
+			t.Errorf("Unexpected stdout route output: %q", stdout.String())
+		}
+		if fallback.String() != "[WRN] WRN\n" {
+			t.Errorf("Unexpected output of unrouted entries: %q", fallback.String())
+		}
+	})
+
+	t.Run("Test routing appenders logger by name and predicate", func(t *testing.T) {
+		database, slow, others := &recordingAppender{}, &recordingAppender{}, &recordingAppender{}
+		routes := []Route{
+			{Match: LoggerNamePattern("db*"), Appenders: []Appender{database}},
+			{Match: func(entry *LogEntry) bool { return len(entry.Fields) > 0 }, Appenders: []Appender{slow}},
+		}
+		dbLogger, _ := newLogger("db.pool", SyncedAppenders().WithAppenders(others).WithRoutes(routes...).(*options))
+		apiLogger, _ := newLogger("api", SyncedAppenders().WithAppenders(others).WithRoutes(routes...).(*options))
+
+		dbLogger.Warning("pool exhausted", F("wait", 5))
+		apiLogger.Warning("slow request", F("wait", 2))
+		apiLogger.Warning("bad request")
+
+		if len(database.entries) != 1 || len(slow.entries) != 2 || len(others.entries) != 1 || others.entries[0].Message != "bad request\n" {
+			t.Error("Unexpected routing of entries :", len(database.entries), len(slow.entries), len(others.entries))
+		}
+	})
+}
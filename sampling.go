@@ -0,0 +1,223 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultSummaryInterval is the default interval between summaries of entries suppressed by sampling
+const DefaultSummaryInterval = time.Minute
+
+// SuppressedKey is the key of the field holding the number of suppressed entries in a suppression summary entry
+const SuppressedKey = "suppressed"
+
+// Sampler decides which entries of a logger are logged, allowing to limit the output of hot code paths. Samplers are
+// stateful, an instance should not be shared by several loggers unless they should share the same budget.
+type Sampler interface {
+
+	// Sample returns true if an entry at the given level should be logged
+	Sample(level int) bool
+}
+
+// samplingPeriod holds the number of entries seen for a level in the current period
+type samplingPeriod struct {
+	start time.Time
+	count int
+}
+
+// firstThenEvery sampler logging the first entries of each level per interval and then every nth
+type firstThenEvery struct {
+	first      int
+	thereafter int
+	interval   time.Duration
+	periods    map[int]*samplingPeriod
+	mutex      sync.Mutex
+}
+
+// FirstThenEvery creates a sampler which logs the first entries of each level in every interval and then only every
+// thereafter-th entry until the interval ends. A thereafter lower than 1 drops all entries after the first ones.
+func FirstThenEvery(first int, thereafter int, interval time.Duration) Sampler {
+	return &firstThenEvery{
+		first:      first,
+		thereafter: thereafter,
+		interval:   interval,
+		periods:    make(map[int]*samplingPeriod),
+	}
+}
+
+// Sample returns true for the first entries of the level in the current interval and for every thereafter-th after that
+func (s *firstThenEvery) Sample(level int) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	period, found := s.periods[level]
+	if !found || now.Sub(period.start) >= s.interval {
+		period = &samplingPeriod{start: now}
+		s.periods[level] = period
+	}
+	period.count++
+	if period.count <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (period.count-s.first)%s.thereafter == 0
+}
+
+// probabilistic sampler logging entries with a given probability
+type probabilistic struct {
+	rate   float64
+	random *rand.Rand
+	mutex  sync.Mutex
+}
+
+// Probabilistic creates a sampler which logs each entry with the given probability (from 0 to 1)
+func Probabilistic(rate float64) Sampler {
+	return &probabilistic{
+		rate:   rate,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Sample returns true with the sampler's probability
+func (s *probabilistic) Sample(int) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.random.Float64() < s.rate
+}
+
+// tokenBucket holds the available tokens for a level
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimit sampler implementing a token bucket for each level
+type rateLimit struct {
+	rate    float64
+	burst   int
+	buckets map[int]*tokenBucket
+	mutex   sync.Mutex
+}
+
+// RateLimit creates a sampler limiting the entries of each level to the given rate per second, allowing bursts of up
+// to burst entries
+func RateLimit(rate float64, burst int) Sampler {
+	return &rateLimit{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[int]*tokenBucket),
+	}
+}
+
+// Sample returns true if a token is available in the level's bucket
+func (s *rateLimit) Sample(level int) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	bucket, found := s.buckets[level]
+	if !found {
+		bucket = &tokenBucket{tokens: float64(s.burst), last: now}
+		s.buckets[level] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * s.rate
+	if bucket.tokens > float64(s.burst) {
+		bucket.tokens = float64(s.burst)
+	}
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// sampling holds the samplers of a logger and keeps track of the suppressed entries, periodically reporting them
+// through a summary
+type sampling struct {
+	samplers        []Sampler                  // the sampler of each level (nil for levels which are not sampled)
+	summaryInterval time.Duration              // the interval between summaries of suppressed entries
+	summarize       func(level int, count int) // function logging the summary of suppressed entries for a level
+	suppressed      map[int]int                // number of entries suppressed since the last summary by level
+	timer           *time.Timer                // timer for the next summary, if entries were suppressed
+	mutex           sync.Mutex
+}
+
+// newSampling creates the sampling of a logger from its options, or nil if the logger is not sampled
+func newSampling(o *options, summarize func(level int, count int)) *sampling {
+	for _, sampler := range o.samplers {
+		if sampler != nil {
+			summaryInterval := o.summaryInterval
+			if summaryInterval <= 0 {
+				summaryInterval = DefaultSummaryInterval
+			}
+			return &sampling{
+				samplers:        o.samplers,
+				summaryInterval: summaryInterval,
+				summarize:       summarize,
+				suppressed:      make(map[int]int),
+			}
+		}
+	}
+	return nil
+}
+
+// sample returns true if an entry at the given level should be logged, keeping count of the suppressed ones
+func (s *sampling) sample(level int) bool {
+	if s == nil || level < 0 || level >= len(s.samplers) || s.samplers[level] == nil || s.samplers[level].Sample(level) {
+		return true
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.suppressed[level]++
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.summaryInterval, s.report)
+	}
+	return false
+}
+
+// report logs the summary of the entries suppressed since the last report
+func (s *sampling) report() {
+	s.mutex.Lock()
+	suppressed := s.suppressed
+	s.suppressed = make(map[int]int)
+	s.timer = nil
+	s.mutex.Unlock()
+
+	for level := 0; level < len(s.samplers); level++ {
+		if count := suppressed[level]; count > 0 {
+			s.summarize(level, count)
+		}
+	}
+}
+
+// flush reports the entries suppressed so far without waiting for the summary interval to elapse
+func (s *sampling) flush() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	pending := s.timer != nil && s.timer.Stop()
+	s.mutex.Unlock()
+	if pending {
+		s.report()
+	}
+}
+
+// newSummaryEntry creates the log entry reporting the number of suppressed entries for a level
+func newSummaryEntry(name string, level int, count int) *LogEntry {
+	return &LogEntry{
+		Logger:    name,
+		Timestamp: time.Now(),
+		Level:     level,
+		Message:   fmt.Sprintf("suppressed %d messages", count),
+		Fields:    []Field{F(SuppressedKey, count)},
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSamplers(t *testing.T) {
+	t.Run("Test first then every sampler", func(t *testing.T) {
+		sampler := FirstThenEvery(2, 3, time.Hour)
+		var sampled []int
+		for i := 1; i <= 10; i++ {
+			if sampler.Sample(ERROR) {
+				sampled = append(sampled, i)
+			}
+		}
+		if len(sampled) != 4 || sampled[0] != 1 || sampled[1] != 2 || sampled[2] != 5 || sampled[3] != 8 {
+			t.Error("Unexpected sampled entries :", sampled)
+		}
+		if !sampler.Sample(WARNING) {
+			t.Error("Levels should be sampled independently")
+		}
+	})
+
+	t.Run("Test probabilistic sampler", func(t *testing.T) {
+		always, never := Probabilistic(1), Probabilistic(0)
+		for i := 0; i < 100; i++ {
+			if !always.Sample(ERROR) || never.Sample(ERROR) {
+				t.Fatal("Probabilistic sampler not respecting its rate")
+			}
+		}
+	})
+
+	t.Run("Test rate limit sampler", func(t *testing.T) {
+		sampler := RateLimit(0.001, 2)
+		if !sampler.Sample(ERROR) || !sampler.Sample(ERROR) {
+			t.Error("Rate limit not allowing burst")
+		}
+		if sampler.Sample(ERROR) {
+			t.Error("Rate limit allowing entries over burst")
+		}
+	})
+}
+
+func TestSampling(t *testing.T) {
+	t.Run("Test suppressed entries summary", func(t *testing.T) {
+		appender := &lockedAppender{}
+		logger, _ := newLogger("SAMPLED", SyncedAppenders().WithAppenders(appender).
+			WithLevelSampler(ERROR, FirstThenEvery(1, 0, time.Hour)).
+			WithSummaryInterval(time.Hour).(*options))
+
+		for i := 0; i < 5; i++ {
+			logger.Error("ERR")
+			logger.Warning("WRN")
+		}
+		if messages := appender.messages(); len(messages) != 6 {
+			t.Error("Unexpected entries while sampling :", messages)
+		}
+
+		logger.(managedLogger).flush()
+		if messages := appender.messages(); len(messages) != 7 || messages[6] != "suppressed 4 messages" {
+			t.Error("Suppression summary not logged :", messages)
+		}
+	})
+
+	t.Run("Test summary logged after interval", func(t *testing.T) {
+		appender := &lockedAppender{}
+		logger, _ := newLogger("SAMPLED", SyncedAppenders().WithAppenders(appender).
+			WithLevelSampler(ERROR, FirstThenEvery(1, 0, time.Hour)).
+			WithSummaryInterval(time.Millisecond).(*options))
+
+		logger.Error("ERR")
+		logger.Error("ERR")
+		if !eventually(func() bool { return len(appender.messages()) == 2 }) {
+			t.Fatal("Suppression summary not logged :", appender.messages())
+		}
+		if messages := appender.messages(); messages[1] != "suppressed 1 messages" {
+			t.Error("Unexpected suppression summary :", messages)
+		}
+	})
+}
+
+func TestInvalidLevelSampler(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Sampler for a negative level should panic")
+		}
+	}()
+	Standard().WithLevelSampler(-1, FirstThenEvery(1, 0, time.Hour))
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"runtime"
+)
+
+// maxStackDepth is the maximum number of frames captured in a stack trace
+const maxStackDepth = 64
+
+// StackFrame holds the information of a single frame of a captured stack trace
+type StackFrame struct {
+	Function string `json:"function"` // name of the function, qualified with its receiver type for methods
+	Package  string `json:"package"`  // import path of the package of the function
+	File     string `json:"file"`     // full path of the source file
+	Line     int    `json:"line"`     // line in the source file
+}
+
+// StackTracer is implemented by errors carrying the stack trace of where they were raised. When such an error is
+// logged at a level with stack trace capture, its stack trace is used instead of the logging goroutine's.
+type StackTracer interface {
+	StackTrace() []StackFrame
+}
+
+// callersProvider is implemented by errors carrying the program counters of where they were raised
+type callersProvider interface {
+	Callers() []uintptr
+}
+
+// framesFromPCs converts program counters into stack frames
+func framesFromPCs(pcs []uintptr) []StackFrame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	stack := make([]StackFrame, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		pkg, function := splitFunctionName(frame.Function)
+		stack = append(stack, StackFrame{Function: function, Package: pkg, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// reflectedStackTrace gets the stack trace of errors providing a StackTrace() method returning program counters in a
+// type unknown to the package (like github.com/pkg/errors)
+func reflectedStackTrace(e error) []StackFrame {
+	method := reflect.ValueOf(e).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+	if t := method.Type().Out(0); t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return framesFromPCs(pcs)
+}
+
+// errorStackTrace gets the stack trace carried by an error or any of the errors it wraps. The innermost stack trace
+// is used as it's the closest to the origin of the error.
+func errorStackTrace(e error) []StackFrame {
+	var stack []StackFrame
+	for ; e != nil; e = errors.Unwrap(e) {
+		switch tracer := e.(type) {
+		case StackTracer:
+			if frames := tracer.StackTrace(); len(frames) > 0 {
+				stack = frames
+			}
+		case callersProvider:
+			if frames := framesFromPCs(tracer.Callers()); len(frames) > 0 {
+				stack = frames
+			}
+		default:
+			if frames := reflectedStackTrace(e); len(frames) > 0 {
+				stack = frames
+			}
+		}
+	}
+	return stack
+}
+
+// captureStack sets the stack trace of the log entry. The stack trace of the first logged error argument carrying one
+// is used, otherwise the stack of the current goroutine is captured starting at the given call depth, relative to
+// the function calling captureStack.
+func (logEntry *LogEntry) captureStack(callDepth int, v []interface{}) {
+	for _, arg := range v {
+		if field, isField := arg.(Field); isField {
+			arg = field.Value
+		}
+		if e, isError := arg.(error); isError && e != nil {
+			if stack := errorStackTrace(e); len(stack) > 0 {
+				logEntry.Stack = stack
+				return
+			}
+		}
+	}
+	pcs := make([]uintptr, maxStackDepth)
+	logEntry.Stack = framesFromPCs(pcs[:runtime.Callers(callDepth+2, pcs)])
+}
+
+// stacktobuf appends the stack trace to the buffer, either as an indented block of function and source lines or as
+// a JSON array in a single line
+func stacktobuf(buf *[]byte, stack []StackFrame, format uint) {
+	if format == StackTraceJSON {
+		if encoded, e := json.Marshal(stack); e == nil {
+			*buf = append(*buf, encoded...)
+			*buf = append(*buf, '\n')
+			return
+		}
+	}
+	for _, frame := range stack {
+		*buf = append(*buf, '\t')
+		if len(frame.Package) > 0 {
+			*buf = append(*buf, frame.Package...)
+			*buf = append(*buf, '.')
+		}
+		*buf = append(*buf, frame.Function...)
+		*buf = append(*buf, "\n\t\t"...)
+		sourcetobuf(buf, frame.File, frame.Line)
+		*buf = append(*buf, '\n')
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type tracedError struct {
+	stack []StackFrame
+}
+
+func (e *tracedError) Error() string {
+	return "traced error"
+}
+
+func (e *tracedError) StackTrace() []StackFrame {
+	return e.stack
+}
+
+type recordingAppender struct {
+	entries []*LogEntry
+}
+
+func (ra *recordingAppender) Print(entry *LogEntry) {
+	ra.entries = append(ra.entries, entry)
+}
+
+func TestStackTraces(t *testing.T) {
+	t.Run("Test stack trace capture threshold", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("STACK", SyncedAppenders().WithAppenders(appender).WithStackTrace(ERROR).(*options))
+		logger.Error("ERR")
+		logger.Warning("WRN")
+
+		if len(appender.entries) != 2 {
+			t.Fatal("Unexpected number of entries :", len(appender.entries))
+		}
+		if stack := appender.entries[0].Stack; len(stack) == 0 {
+			t.Error("Stack trace not captured for ERROR entry")
+		} else if stack[0].Function != "TestStackTraces.func1" || stack[0].Package != "github.com/gomatbase/go-log" {
+			t.Error("Stack trace not starting at the logging function :", stack[0])
+		}
+		if appender.entries[1].Stack != nil {
+			t.Error("Stack trace captured for WARNING entry")
+		}
+	})
+
+	t.Run("Test stack trace from error", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("STACK", SyncedAppenders().WithAppenders(appender).WithStackTrace(ERROR).(*options))
+		e := &tracedError{stack: []StackFrame{{Function: "raise", Package: "errors/origin", File: "origin.go", Line: 10}}}
+		logger.Errorf("failed: %v", e)
+
+		if stack := appender.entries[0].Stack; len(stack) != 1 || stack[0] != e.stack[0] {
+			t.Error("Error stack trace not used :", stack)
+		}
+	})
+
+	t.Run("Test indented stack trace", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger, _ := newLogger("STACK", Standard().WithWriter(buffer).WithStackTrace(CRITICAL).(*options))
+		logger.Critical(&tracedError{stack: []StackFrame{{Function: "raise", Package: "errors/origin", File: "/src/origin.go", Line: 10}}})
+
+		if buffer.String() != "traced error\n\terrors/origin.raise\n\t\t/src/origin.go:10\n" {
+			t.Errorf("Unexpected indented stack trace output: %q", buffer.String())
+		}
+	})
+
+	t.Run("Test JSON stack trace", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger, _ := newLogger("STACK", Standard().WithWriter(buffer).WithStackTrace(CRITICAL).WithStackTraceFormat(StackTraceJSON).(*options))
+		logger.Critical("CRT")
+
+		lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+		var stack []StackFrame
+		if len(lines) != 2 || lines[0] != "CRT" {
+			t.Fatalf("Unexpected JSON stack trace output: %q", buffer.String())
+		} else if e := json.Unmarshal([]byte(lines[1]), &stack); e != nil {
+			t.Error("Stack trace is not a JSON array :", e)
+		} else if len(stack) == 0 || stack[0].Function != "TestStackTraces.func4" {
+			t.Error("Unexpected JSON stack trace :", stack)
+		}
+	})
+}
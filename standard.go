@@ -5,12 +5,14 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
-	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,157 +27,437 @@ type headerFormat struct {
 
 // logger Simple implementation writing to an ioWriter as output.
 type standardLogger struct {
-	options         *options // the original options used to create the logger
-	level           int      // the current log level
+	options         *options     // the options the logger is configured with
+	level           atomic.Int32 // the current log level
 	name            string
 	writer          io.Writer
 	buffer          []byte
 	levelFormats    []headerFormat
 	mutex           sync.Mutex
-	criticalFailure bool
+	criticalFailure atomic.Bool
 	callDepth       int
+	sampling        atomic.Pointer[sampling]
+	deduplicator    *deduplicator
+	overrides       levelOverrides
+	verbosity       verbosity
 }
 
 func newStandardLogger(name string, options *options) Logger {
+	callDepth := 3
+	if name == DEFAULT {
+		callDepth = 4
+	}
+	logger := &standardLogger{
+		name:      name,
+		callDepth: callDepth,
+	}
+	options = options.clone()
+	logger.configure(options)
+	logger.level.Store(int32(options.startingLevel))
+	logger.verbosity.set(options.verbosity, options.vmodules)
+	return logger
+}
+
+// configure sets up the logger from the options, keeping its sampling and deduplication state unless their options
+// changed. The replaced sampling and deduplicator are returned, to be flushed once the logger's lock is released. Must
+// be called while holding the logger's lock if the logger is in use.
+func (logger *standardLogger) configure(options *options) (*sampling, *deduplicator) {
 	levelFormats := make([]headerFormat, len(options.levelFormats))
 	for i, levelFormat := range options.levelFormats {
 		levelFormats[i] = headerFormat{format: levelFormat}
 		for _, format := range levelFormat {
-			if format == Source || format == LongSource {
+			if format == Source || format == LongSource || format == Function || format == Package || format == ModuleSource {
 				levelFormats[i].hasSource = true
 				break
 			}
 		}
 	}
-	callDepth := 3
-	if name == DEFAULT {
-		callDepth = 4
+	writer := options.writer
+	if writer == nil {
+		writer = os.Stdout
 	}
-	return &standardLogger{
-		options:         options,
-		level:           options.startingLevel,
-		name:            name,
-		writer:          os.Stdout,
-		levelFormats:    levelFormats,
-		criticalFailure: options.failingCriticals,
-		callDepth:       callDepth,
+	previous := logger.options
+	logger.options = options
+	logger.writer = writer
+	logger.levelFormats = levelFormats
+	logger.criticalFailure.Store(options.failingCriticals)
+	var replacedSampling *sampling
+	var replacedDeduplicator *deduplicator
+	if previous == nil || !previous.sameSampling(options) {
+		replacedSampling = logger.sampling.Swap(newSampling(options, logger.summarize))
 	}
+	if previous == nil || previous.dedupWindow != options.dedupWindow {
+		replacedDeduplicator = logger.deduplicator
+		logger.deduplicator = newDeduplicator(options.dedupWindow, logger.repeat)
+	}
+	return replacedSampling, replacedDeduplicator
+}
+
+// reconfigure applies a copy of the options to the logger, including its starting level, flushing the entries held
+// back by a replaced sampling or deduplication
+func (logger *standardLogger) reconfigure(options *options) {
+	options = options.clone()
+	logger.mutex.Lock()
+	sampling, deduplicator := logger.configure(options)
+	logger.overrides.set(&logger.level, options.startingLevel)
+	logger.verbosity.set(options.verbosity, options.vmodules)
+	logger.mutex.Unlock()
+	sampling.flush()
+	deduplicator.flush()
+}
+
+// update changes a copy of the logger's options and applies it, keeping the current level
+func (logger *standardLogger) update(change func(o *options)) {
+	logger.mutex.Lock()
+	o := logger.options.clone()
+	change(o)
+	sampling, deduplicator := logger.configure(o)
+	logger.mutex.Unlock()
+	sampling.flush()
+	deduplicator.flush()
+}
+
+// loggerOptions returns the options the logger is configured with
+func (logger *standardLogger) loggerOptions() *options {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	return logger.options
+}
+
+// setCallDepth sets the number of frames to skip to get to the caller of the logging function
+func (logger *standardLogger) setCallDepth(callDepth int) {
+	logger.callDepth = callDepth
+}
+
+// flush writes the summaries of suppressed entries and the collapsed repetitions without waiting
+func (logger *standardLogger) flush() {
+	logger.sampling.Load().flush()
+	logger.mutex.Lock()
+	deduplicator := logger.deduplicator
+	logger.mutex.Unlock()
+	deduplicator.flush()
 }
 
 // SetLevel sets the level of the logger
 func (logger *standardLogger) SetLevel(level int) {
-	logger.level = level
+	logger.overrides.set(&logger.level, level)
 }
 
 func (logger *standardLogger) Level() int {
-	return logger.level
+	return int(logger.level.Load())
+}
+
+// Enabled returns true if entries at the given level are logged by the logger
+func (logger *standardLogger) Enabled(level int) bool {
+	return level <= logger.Level()
+}
+
+// EnabledCtx returns true if entries at the given level are logged by the logger when logged with the context
+func (logger *standardLogger) EnabledCtx(ctx context.Context, level int) bool {
+	return enabled(ctx, logger.Level(), level)
+}
+
+// V returns the handle logging entries of the given verbosity level
+func (logger *standardLogger) V(level int) Verbose {
+	return Verbose{logger: logger, level: level, enabled: logger.verbose(level, 1)}
+}
+
+// SetVerbosity sets the verbosity of the logger
+func (logger *standardLogger) SetVerbosity(verbosity int) {
+	logger.verbosity.level.Store(int32(verbosity))
+}
+
+// Verbosity returns the verbosity of the logger
+func (logger *standardLogger) Verbosity() int {
+	return int(logger.verbosity.level.Load())
+}
+
+// verbose returns true if the given verbosity level is enabled for the caller at the given call depth
+func (logger *standardLogger) verbose(level int, callDepth int) bool {
+	return logger.verbosity.enabled(level, callDepth+1)
+}
+
+// vmodules returns the current verbosity of the source files matching patterns
+func (logger *standardLogger) vmodules() []VModule {
+	return logger.verbosity.vmodules()
+}
+
+// SetVModule sets the verbosity of the source files matching patterns
+func (logger *standardLogger) SetVModule(spec string) error {
+	modules, e := ParseVModule(spec)
+	if e != nil {
+		return e
+	}
+	logger.verbosity.setModules(modules)
+	return nil
+}
+
+// PushLevel temporarily sets the level of the logger until the returned function is called
+func (logger *standardLogger) PushLevel(level int) func() {
+	return logger.overrides.push(&logger.level, level)
+}
+
+// OverrideLevel temporarily sets the level of the logger for the given duration
+func (logger *standardLogger) OverrideLevel(level int, duration time.Duration) func() {
+	return logger.overrides.override(&logger.level, level, duration)
+}
+
+// Writer returns a writer logging each line written to it as an entry at the given level
+func (logger *standardLogger) Writer(level int) io.WriteCloser {
+	return NewLineWriter(logger, level, nil)
+}
+
+// SetWriter swaps the writer the logger writes to
+func (logger *standardLogger) SetWriter(writer io.Writer) error {
+	logger.update(func(o *options) {
+		o.writer = writer
+	})
+	return nil
+}
+
+// SetAppenders is not supported by standard loggers
+func (logger *standardLogger) SetAppenders(...Appender) error {
+	return ErrUnsupportedByLoggerType
+}
+
+// SetLevelLogPrefix sets the log prefix format for a specific level
+func (logger *standardLogger) SetLevelLogPrefix(level int, flags ...uint) {
+	logger.update(func(o *options) {
+		o.WithLevelLogPrefix(level, flags...)
+	})
+}
+
+// SetLogPrefix sets the log prefix format for all levels
+func (logger *standardLogger) SetLogPrefix(flags ...uint) {
+	logger.update(func(o *options) {
+		o.WithLogPrefix(flags...)
+	})
+}
+
+// SetDateFlags sets the format flags of the date in the log prefix
+func (logger *standardLogger) SetDateFlags(flags int) {
+	logger.update(func(o *options) {
+		o.dateFlags = flags
+	})
+}
+
+// SetFailingCriticals sets if logging a critical should result in a fatal entry (panic)
+func (logger *standardLogger) SetFailingCriticals(failing bool) {
+	logger.update(func(o *options) {
+		o.failingCriticals = failing
+	})
 }
 
 func (logger *standardLogger) Critical(v ...interface{}) {
-	logger.println(CRITICAL, v...)
+	logger.println(nil, CRITICAL, v...)
 }
 
 func (logger *standardLogger) Criticalf(format string, v ...interface{}) {
-	logger.printf(CRITICAL, format, v...)
+	logger.printf(nil, CRITICAL, format, v...)
 }
 
 func (logger *standardLogger) Error(v ...interface{}) {
-	logger.println(ERROR, v...)
+	logger.println(nil, ERROR, v...)
 }
 
 func (logger *standardLogger) Errorf(format string, v ...interface{}) {
-	logger.printf(ERROR, format, v...)
+	logger.printf(nil, ERROR, format, v...)
 }
 
 func (logger *standardLogger) Warning(v ...interface{}) {
-	logger.println(WARNING, v...)
+	logger.println(nil, WARNING, v...)
 }
 
 func (logger *standardLogger) Warningf(format string, v ...interface{}) {
-	logger.printf(WARNING, format, v...)
+	logger.printf(nil, WARNING, format, v...)
 }
 
 func (logger *standardLogger) Info(v ...interface{}) {
-	logger.println(INFO, v...)
+	logger.println(nil, INFO, v...)
 }
 
 func (logger *standardLogger) Infof(format string, v ...interface{}) {
-	logger.printf(INFO, format, v...)
+	logger.printf(nil, INFO, format, v...)
 }
 
 func (logger *standardLogger) Debug(v ...interface{}) {
-	logger.println(DEBUG, v...)
+	logger.println(nil, DEBUG, v...)
 }
 
 func (logger *standardLogger) Debugf(format string, v ...interface{}) {
-	logger.printf(DEBUG, format, v...)
+	logger.printf(nil, DEBUG, format, v...)
 }
 
 func (logger *standardLogger) Trace(v ...interface{}) {
-	logger.println(TRACE, v...)
+	logger.println(nil, TRACE, v...)
 }
 
 func (logger *standardLogger) Tracef(format string, v ...interface{}) {
-	logger.printf(TRACE, format, v...)
+	logger.printf(nil, TRACE, format, v...)
+}
+
+func (logger *standardLogger) CriticalCtx(ctx context.Context, v ...interface{}) {
+	logger.println(ctx, CRITICAL, v...)
+}
+
+func (logger *standardLogger) CriticalfCtx(ctx context.Context, format string, v ...interface{}) {
+	logger.printf(ctx, CRITICAL, format, v...)
+}
+
+func (logger *standardLogger) ErrorCtx(ctx context.Context, v ...interface{}) {
+	logger.println(ctx, ERROR, v...)
+}
+
+func (logger *standardLogger) ErrorfCtx(ctx context.Context, format string, v ...interface{}) {
+	logger.printf(ctx, ERROR, format, v...)
+}
+
+func (logger *standardLogger) WarningCtx(ctx context.Context, v ...interface{}) {
+	logger.println(ctx, WARNING, v...)
+}
+
+func (logger *standardLogger) WarningfCtx(ctx context.Context, format string, v ...interface{}) {
+	logger.printf(ctx, WARNING, format, v...)
+}
+
+func (logger *standardLogger) InfoCtx(ctx context.Context, v ...interface{}) {
+	logger.println(ctx, INFO, v...)
+}
+
+func (logger *standardLogger) InfofCtx(ctx context.Context, format string, v ...interface{}) {
+	logger.printf(ctx, INFO, format, v...)
+}
+
+func (logger *standardLogger) DebugCtx(ctx context.Context, v ...interface{}) {
+	logger.println(ctx, DEBUG, v...)
+}
+
+func (logger *standardLogger) DebugfCtx(ctx context.Context, format string, v ...interface{}) {
+	logger.printf(ctx, DEBUG, format, v...)
+}
+
+func (logger *standardLogger) TraceCtx(ctx context.Context, v ...interface{}) {
+	logger.println(ctx, TRACE, v...)
+}
+
+func (logger *standardLogger) TracefCtx(ctx context.Context, format string, v ...interface{}) {
+	logger.printf(ctx, TRACE, format, v...)
 }
 
-func (logger *standardLogger) println(level int, v ...interface{}) {
-	if level <= logger.level {
-		logger.output(level, logger.callDepth, fmt.Sprintln(v...))
+func (logger *standardLogger) println(ctx context.Context, level int, v ...interface{}) {
+	if logger.EnabledCtx(ctx, level) && logger.sampling.Load().sample(level) {
+		logger.output(ctx, level, logger.callDepth, fmt.Sprintln(messageValues(v)...), v)
 	}
-	if level == 0 && logger.criticalFailure {
+	if level == 0 && logger.criticalFailure.Load() {
 		panic("critical failure")
 	}
 }
 
-func (logger *standardLogger) printf(level int, format string, v ...interface{}) {
-	if level <= logger.level {
-		logger.output(level, logger.callDepth, fmt.Sprintf(format, v...))
+func (logger *standardLogger) printf(ctx context.Context, level int, format string, v ...interface{}) {
+	if logger.EnabledCtx(ctx, level) && logger.sampling.Load().sample(level) {
+		logger.output(ctx, level, logger.callDepth, fmt.Sprintf(format, messageValues(v)...), v)
 	}
-	if level == 0 && logger.criticalFailure {
+	if level == 0 && logger.criticalFailure.Load() {
 		panic("critical failure")
 	}
 }
 
-func (logger *standardLogger) output(level int, callDepth int, s string) error {
+func (logger *standardLogger) output(ctx context.Context, level int, callDepth int, s string, v []interface{}) error {
 	logger.mutex.Lock()
 	defer logger.mutex.Unlock()
-	if level > logger.level {
+	if !logger.EnabledCtx(ctx, level) {
 		return nil
 	}
 
-	now := time.Now()
-
-	var file string
-	var line int
-	levelHeaderFormat := logger.levelFormats[level]
-	if levelHeaderFormat.hasSource {
-		// Release lock while getting caller info - it's expensive.
+	entry := &LogEntry{
+		Logger:    logger.name,
+		Timestamp: time.Now(),
+		Level:     level,
+		Message:   s,
+	}
+	entry.recordContext(ctx)
+	entry.recordValues(v)
+	logger.options.redactor.Transform(entry)
+	var levelHeaderFormat headerFormat
+	if level >= 0 && level < len(logger.levelFormats) {
+		levelHeaderFormat = logger.levelFormats[level]
+	}
+	if levelHeaderFormat.hasSource || len(logger.options.routes) > 0 {
+		// Release lock while getting caller info - it's expensive. Routes always get it as their format is unknown.
 		logger.mutex.Unlock()
-		var ok bool
-		_, file, line, ok = runtime.Caller(callDepth)
-		if !ok {
-			file = "???"
-			line = 0
-		}
+		entry.captureCaller(callDepth)
 		logger.mutex.Lock()
 	}
-	logger.buffer = logger.buffer[:0]
-	logger.formatHeader(&logger.buffer, level, now, file, line, levelHeaderFormat.format)
-	logger.buffer = append(logger.buffer, s...)
-	if len(s) == 0 || s[len(s)-1] != '\n' {
-		logger.buffer = append(logger.buffer, '\n')
+	if level <= logger.options.stackTraceLevel {
+		logger.mutex.Unlock()
+		entry.captureStack(callDepth, v)
+		logger.mutex.Lock()
+	}
+	if !logger.deduplicator.filter(entry) {
+		return nil
+	}
+	return logger.write(entry)
+}
+
+// log outputs an entry built outside the logger, with its caller already resolved
+func (logger *standardLogger) log(entry *LogEntry, enabled bool) {
+	if (enabled || logger.Enabled(entry.Level)) && logger.sampling.Load().sample(entry.Level) {
+		logger.mutex.Lock()
+		entry.Logger = logger.name
+		logger.options.redactor.Transform(entry)
+		if logger.deduplicator.filter(entry) {
+			_ = logger.write(entry)
+		}
+		logger.mutex.Unlock()
+	}
+	if entry.Level == CRITICAL && logger.criticalFailure.Load() {
+		panic("critical failure")
+	}
+}
+
+// repeat writes the entry collapsing the repetitions of an entry
+func (logger *standardLogger) repeat(entry *LogEntry) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	_ = logger.write(entry)
+}
+
+// summarize writes the summary of entries suppressed by sampling for a level
+func (logger *standardLogger) summarize(level int, count int) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	_ = logger.write(newSummaryEntry(logger.name, level, count))
+}
+
+// write formats the entry and writes it to the logger's writer. Must be called while holding the logger's lock.
+func (logger *standardLogger) write(entry *LogEntry) error {
+	if entry = logger.options.pipeline.Process(entry); entry == nil {
+		return nil
+	}
+	if len(logger.options.routes) > 0 && route(logger.options.routes, entry, &logger.buffer, logger.format) {
+		return nil
 	}
+	logger.buffer = logger.buffer[:0]
+	logger.format(&logger.buffer, entry)
 	_, err := logger.writer.Write(logger.buffer)
 	return err
 }
 
+// format formats the entry in the logger's format for its level
+func (logger *standardLogger) format(buf *[]byte, entry *LogEntry) {
+	var format []uint
+	if entry.Level >= 0 && entry.Level < len(logger.levelFormats) {
+		format = logger.levelFormats[entry.Level].format
+	}
+	formatEntry(buf, entry, format, logger.options.dateFlags, logger.options.stackTraceFormat)
+}
+
 var digits = []byte{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9'}
 
 func itoa(buf *[]byte, i int, padding int) {
 	var b [20]byte
 	n := 19
-	for i > 10 || padding > 1 {
+	for i >= 10 || padding > 1 {
 		padding--
 		m := i % 10
 		b[n] = digits[m]
@@ -220,22 +502,61 @@ func sourcetobuf(buf *[]byte, source string, line int) {
 	itoa(buf, line, 0)
 }
 
-func (logger *standardLogger) formatHeader(buf *[]byte, level int, t time.Time, file string, line int, format []uint) {
+// fieldtobuf appends a field to the buffer as " key=value", quoting the value if it holds spaces, quotes or '='
+func fieldtobuf(buf *[]byte, field Field) {
+	*buf = append(*buf, ' ')
+	*buf = append(*buf, field.Key...)
+	*buf = append(*buf, '=')
+	value := fmt.Sprint(field.Value)
+	if strings.ContainsAny(value, " \t\n\"=") {
+		*buf = strconv.AppendQuote(*buf, value)
+	} else {
+		*buf = append(*buf, value...)
+	}
+}
+
+// formatEntry formats the entry as a line with the header in the given format followed by the message and fields,
+// and the stack trace in the following lines if the entry has one
+func formatEntry(buf *[]byte, entry *LogEntry, format []uint, dateFlags int, stackTraceFormat uint) {
+	formatHeader(buf, entry, format, dateFlags)
+	*buf = append(*buf, strings.TrimSuffix(entry.Message, "\n")...)
+	for _, field := range entry.Fields {
+		fieldtobuf(buf, field)
+	}
+	*buf = append(*buf, '\n')
+	if len(entry.Stack) > 0 {
+		stacktobuf(buf, entry.Stack, stackTraceFormat)
+	}
+}
+
+// formatHeader appends the header of the entry in the given format to the buffer
+func formatHeader(buf *[]byte, entry *LogEntry, format []uint, dateFlags int) {
 	for _, f := range format {
 		switch f {
 		case Separator:
 			*buf = append(*buf, '-')
 		case Name:
-			*buf = append(*buf, logger.name...)
+			*buf = append(*buf, entry.Logger...)
 		case LogLevel:
-			*buf = append(*buf, levelTokens[level]...)
+			if entry.Level >= 0 && entry.Level < len(levelTokens) {
+				*buf = append(*buf, levelTokens[entry.Level]...)
+			} else {
+				*buf = append(*buf, "[???]"...)
+			}
 		case LongSource:
-			sourcetobuf(buf, file, line)
+			sourcetobuf(buf, entry.sourceFile(), entry.Line)
 		case Source:
+			file := entry.sourceFile()
 			i := strings.LastIndexByte(file, '/')
-			sourcetobuf(buf, file[i+1:], line)
+			sourcetobuf(buf, file[i+1:], entry.Line)
+		case ModuleSource:
+			sourcetobuf(buf, entry.ModuleSource, entry.Line)
+		case Function:
+			*buf = append(*buf, entry.Function...)
+		case Package:
+			*buf = append(*buf, entry.Package...)
 		case Time:
-			timetoa(buf, logger.options.dateFlags, t)
+			timetoa(buf, dateFlags, entry.Timestamp)
 		}
 		*buf = append(*buf, ' ')
 	}
@@ -0,0 +1,53 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestItoa(t *testing.T) {
+	for _, tc := range []struct {
+		i        int
+		padding  int
+		expected string
+	}{
+		{0, 0, "0"},
+		{9, 0, "9"},
+		{10, 0, "10"},
+		{10, 2, "10"},
+		{100, 0, "100"},
+		{7, 2, "07"},
+		{2021, 4, "2021"},
+	} {
+		var buf []byte
+		itoa(&buf, tc.i, tc.padding)
+		if string(buf) != tc.expected {
+			t.Errorf("itoa(%d, %d) produced %q instead of %q", tc.i, tc.padding, buf, tc.expected)
+		}
+	}
+}
+
+func TestTimetoa(t *testing.T) {
+	timestamp := time.Date(2021, 10, 10, 10, 10, 10, 123456000, time.FixedZone("UTC+2", 2*60*60))
+	for _, tc := range []struct {
+		flags    int
+		expected string
+	}{
+		{0, ""},
+		{Ldate, "2021/10/10 "},
+		{Ltime, "10:10:10 "},
+		{Lmicroseconds, "10:10:10.123456 "},
+		{Ldate | Ltime, "2021/10/10 10:10:10 "},
+		{Ldate | Ltime | LUTC, "2021/10/10 08:10:10 "},
+	} {
+		var buf []byte
+		timetoa(&buf, tc.flags, timestamp)
+		if string(buf) != tc.expected {
+			t.Errorf("timetoa with flags %d produced %q instead of %q", tc.flags, buf, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	stdlog "log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StdLine the parts of a line written by a standard library logger
+type StdLine struct {
+	Prefix    string    // prefix of the standard logger, if found
+	Timestamp time.Time // date and time of the header (zero if the flags have neither)
+	Source    string    // source file of the header (Lshortfile or Llongfile)
+	Line      int       // source line of the header
+	Message   string    // message following the header
+}
+
+// ParseStdLine parses a line written by a standard library logger with the given prefix and flags (as in the standard
+// log package, Ldate, Lshortfile, Lmsgprefix...). Parsing stops at the first part of the header not matching the
+// flags, the remainder being the message, in which case false is returned.
+func ParseStdLine(line string, prefix string, flags int) (StdLine, bool) {
+	parsed := StdLine{}
+	rest := strings.TrimSuffix(line, "\n")
+	if flags&stdlog.Lmsgprefix == 0 && !cutPrefix(&rest, prefix, &parsed.Prefix) {
+		parsed.Message = rest
+		return parsed, false
+	}
+	if flags&(stdlog.Ldate|stdlog.Ltime|stdlog.Lmicroseconds) != 0 {
+		layout := ""
+		if flags&stdlog.Ldate != 0 {
+			layout = "2006/01/02 "
+		}
+		if flags&stdlog.Lmicroseconds != 0 {
+			layout += "15:04:05.000000 "
+		} else if flags&stdlog.Ltime != 0 {
+			layout += "15:04:05 "
+		}
+		location := time.Local
+		if flags&stdlog.LUTC != 0 {
+			location = time.UTC
+		}
+		if len(rest) < len(layout) {
+			parsed.Message = rest
+			return parsed, false
+		}
+		timestamp, e := time.ParseInLocation(layout, rest[:len(layout)], location)
+		if e != nil {
+			parsed.Message = rest
+			return parsed, false
+		}
+		parsed.Timestamp = timestamp
+		rest = rest[len(layout):]
+	}
+	if flags&(stdlog.Lshortfile|stdlog.Llongfile) != 0 {
+		end := strings.Index(rest, ": ")
+		if end < 0 {
+			parsed.Message = rest
+			return parsed, false
+		}
+		colon := strings.LastIndexByte(rest[:end], ':')
+		line, e := strconv.Atoi(rest[colon+1 : end])
+		if colon < 0 || e != nil {
+			parsed.Message = rest
+			return parsed, false
+		}
+		parsed.Source, parsed.Line = rest[:colon], line
+		rest = rest[end+2:]
+	}
+	if flags&stdlog.Lmsgprefix != 0 && !cutPrefix(&rest, prefix, &parsed.Prefix) {
+		parsed.Message = rest
+		return parsed, false
+	}
+	parsed.Message = rest
+	return parsed, true
+}
+
+// cutPrefix removes the prefix from the text, recording it, returning false if the text doesn't start with it
+func cutPrefix(text *string, prefix string, found *string) bool {
+	if !strings.HasPrefix(*text, prefix) {
+		return false
+	}
+	*text = (*text)[len(prefix):]
+	*found = prefix
+	return true
+}
+
+// stdWriter writer turning the lines written by a standard library logger into entries of a logger at a fixed level
+type stdWriter struct {
+	logger Logger
+	level  int
+	prefix string // prefix of the standard library logger, stripped from the lines
+	flags  int    // flags of the standard library logger, the header being stripped from the lines
+}
+
+// Write logs the line written by the standard library logger, with the caller of the standard library logger as
+// the source of the entry
+func (sw *stdWriter) Write(p []byte) (int, error) {
+	parsed, _ := ParseStdLine(string(p), sw.prefix, sw.flags)
+	entry := &LogEntry{
+		Timestamp: time.Now(),
+		Level:     sw.level,
+		Message:   parsed.Message,
+	}
+	entry.captureCallerSkipping(1, "log")
+	logEntry(sw.logger, entry)
+	return len(p), nil
+}
+
+// logEntry outputs an entry built outside the logger through the logger. Entries for loggers not implemented by the
+// package are logged through the logging function of their level.
+func logEntry(logger Logger, entry *LogEntry) {
+	if managed, isManaged := logger.(managedLogger); isManaged {
+		managed.log(entry, false)
+		return
+	}
+	logMessage(logger, entry)
+}
+
+// logEnabledEntry outputs an entry built outside the logger through the logger without checking its level
+func logEnabledEntry(logger Logger, entry *LogEntry) {
+	if managed, isManaged := logger.(managedLogger); isManaged {
+		managed.log(entry, true)
+		return
+	}
+	logMessage(logger, entry)
+}
+
+// logMessage logs the message of an entry through the logging function of its level
+func logMessage(logger Logger, entry *LogEntry) {
+	switch entry.Level {
+	case CRITICAL:
+		logger.Critical(entry.Message)
+	case ERROR:
+		logger.Error(entry.Message)
+	case WARNING:
+		logger.Warning(entry.Message)
+	case INFO:
+		logger.Info(entry.Message)
+	case DEBUG:
+		logger.Debug(entry.Message)
+	default:
+		logger.Trace(entry.Message)
+	}
+}
+
+// NewStdLogger creates a standard library logger whose output is logged by the logger at the given level, for
+// libraries only accepting a *log.Logger from the standard library (like http.Server's ErrorLog). The caller of the
+// standard library logger is the source of the entries.
+func NewStdLogger(logger Logger, level int) *stdlog.Logger {
+	return stdlog.New(&stdWriter{logger: logger, level: level}, "", 0)
+}
+
+// RedirectStdLog redirects the output of the standard library default logger to the logger at the given level,
+// returning the function restoring its previous output. The prefix and flags the standard logger has when redirected
+// are stripped from its lines, they should not be changed while redirected.
+func RedirectStdLog(logger Logger, level int) (restore func()) {
+	std := stdlog.Default()
+	previous := std.Writer()
+	std.SetOutput(&stdWriter{logger: logger, level: level, prefix: std.Prefix(), flags: std.Flags()})
+	return func() {
+		std.SetOutput(previous)
+	}
+}
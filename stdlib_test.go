@@ -0,0 +1,84 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	stdlog "log"
+	"testing"
+	"time"
+)
+
+func TestStdLogger(t *testing.T) {
+	appender := &recordingAppender{}
+	logger, _ := newLogger("STD", SyncedAppenders().WithAppenders(appender).(*options))
+
+	t.Run("Test standard library logger adapter", func(t *testing.T) {
+		NewStdLogger(logger, ERROR).Printf("failed %d", 1)
+		NewStdLogger(logger, INFO).Println("filtered")
+		if len(appender.entries) != 1 {
+			t.Fatal("Unexpected entries :", appender.entries)
+		}
+		entry := appender.entries[0]
+		if entry.Logger != "STD" || entry.Level != ERROR || entry.Message != "failed 1" {
+			t.Error("Unexpected entry :", entry)
+		}
+		if entry.Function != "TestStdLogger.func1" || entry.ModuleSource != "stdlib_test.go" {
+			t.Error("Caller of the standard library logger not resolved :", entry.Function, entry.ModuleSource)
+		}
+	})
+
+	t.Run("Test redirecting the standard library default logger", func(t *testing.T) {
+		std := stdlog.Default()
+		previousOutput, previousFlags, previousPrefix := std.Writer(), std.Flags(), std.Prefix()
+		defer func() {
+			std.SetOutput(previousOutput)
+			std.SetFlags(previousFlags)
+			std.SetPrefix(previousPrefix)
+		}()
+		output := &bytes.Buffer{}
+		std.SetOutput(output)
+		std.SetFlags(stdlog.LstdFlags | stdlog.Lshortfile)
+		std.SetPrefix("legacy: ")
+
+		restore := RedirectStdLog(logger, WARNING)
+		stdlog.Print("redirected")
+		restore()
+		stdlog.Print("restored")
+
+		if len(appender.entries) != 2 || appender.entries[1].Message != "redirected" || appender.entries[1].Level != WARNING {
+			t.Error("Unexpected redirected entries :", appender.entries)
+		}
+		if parsed, _ := ParseStdLine(output.String(), "legacy: ", std.Flags()); parsed.Message != "restored" {
+			t.Error("Standard library output not restored :", output.String())
+		}
+	})
+}
+
+func TestParseStdLine(t *testing.T) {
+	for _, tc := range []struct {
+		line    string
+		prefix  string
+		flags   int
+		parsed  StdLine
+		matched bool
+	}{
+		{"message\n", "", 0, StdLine{Message: "message"}, true},
+		{"app: 2009/01/23 01:23:23 main.go:23: message", "app: ", stdlog.LstdFlags | stdlog.Lshortfile,
+			StdLine{Prefix: "app: ", Timestamp: time.Date(2009, 1, 23, 1, 23, 23, 0, time.UTC), Source: "main.go", Line: 23, Message: "message"}, true},
+		{"01:23:23.123456 /src/main.go:7: app: message", "app: ", stdlog.Lmicroseconds | stdlog.Llongfile | stdlog.Lmsgprefix | stdlog.LUTC,
+			StdLine{Prefix: "app: ", Timestamp: time.Date(0, 1, 1, 1, 23, 23, 123456000, time.UTC), Source: "/src/main.go", Line: 7, Message: "message"}, true},
+		{"other: message", "app: ", 0, StdLine{Message: "other: message"}, false},
+		{"2009/01/23 message", "", stdlog.LstdFlags, StdLine{Message: "2009/01/23 message"}, false},
+	} {
+		flags := tc.flags
+		if flags&stdlog.LstdFlags != 0 {
+			flags |= stdlog.LUTC
+		}
+		if parsed, matched := ParseStdLine(tc.line, tc.prefix, flags); parsed != tc.parsed || matched != tc.matched {
+			t.Errorf("Unexpected parsing of %q: %+v (%v)", tc.line, parsed, matched)
+		}
+	}
+}
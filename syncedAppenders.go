@@ -1,138 +1,433 @@
 package log
 
 import (
+	"context"
 	"fmt"
-	"runtime"
+	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// syncedAppenders logger implementation handing log entries, with their source information, to a set of appenders
+// while holding a lock, so appenders don't need to be thread-safe
 type syncedAppenders struct {
-	options         *options // the original options used to create the logger
-	level           int      // the current log level
-	levelHasSource  []bool
+	options         *options     // the options the logger is configured with
+	level           atomic.Int32 // the current log level
 	name            string
-	criticalFailure bool
+	criticalFailure atomic.Bool
+	callDepth       int
 
-	appenders []Appender
-	mutex     sync.Mutex
+	appenders    []Appender
+	buffer       []byte
+	sampling     atomic.Pointer[sampling]
+	deduplicator *deduplicator
+	overrides    levelOverrides
+	verbosity    verbosity
+	mutex        sync.Mutex
 }
 
-func newSyncedAppenders(o *options) *syncedAppenders {
-	return &syncedAppenders{}
+func newSyncedAppenders(name string, o *options) *syncedAppenders {
+	callDepth := 3
+	if name == DEFAULT {
+		callDepth = 4
+	}
+	sa := &syncedAppenders{
+		name:      name,
+		callDepth: callDepth,
+	}
+	o = o.clone()
+	sa.configure(o)
+	sa.level.Store(int32(o.startingLevel))
+	sa.verbosity.set(o.verbosity, o.vmodules)
+	return sa
+}
+
+// configure sets up the logger from the options, keeping its sampling and deduplication state unless their options
+// changed. The replaced sampling and deduplicator are returned, to be flushed once the logger's lock is released. Must
+// be called while holding the logger's lock if the logger is in use.
+func (sa *syncedAppenders) configure(o *options) (*sampling, *deduplicator) {
+	previous := sa.options
+	sa.options = o
+	sa.appenders = o.appenders
+	sa.criticalFailure.Store(o.failingCriticals)
+	var replacedSampling *sampling
+	var replacedDeduplicator *deduplicator
+	if previous == nil || !previous.sameSampling(o) {
+		replacedSampling = sa.sampling.Swap(newSampling(o, sa.summarize))
+	}
+	if previous == nil || previous.dedupWindow != o.dedupWindow {
+		replacedDeduplicator = sa.deduplicator
+		sa.deduplicator = newDeduplicator(o.dedupWindow, sa.print)
+	}
+	return replacedSampling, replacedDeduplicator
+}
+
+// reconfigure applies a copy of the options to the logger, including its starting level, flushing the entries held
+// back by a replaced sampling or deduplication
+func (sa *syncedAppenders) reconfigure(o *options) {
+	o = o.clone()
+	sa.mutex.Lock()
+	sampling, deduplicator := sa.configure(o)
+	sa.overrides.set(&sa.level, o.startingLevel)
+	sa.verbosity.set(o.verbosity, o.vmodules)
+	sa.mutex.Unlock()
+	sampling.flush()
+	deduplicator.flush()
+}
+
+// update changes a copy of the logger's options and applies it, keeping the current level
+func (sa *syncedAppenders) update(change func(o *options)) {
+	sa.mutex.Lock()
+	o := sa.options.clone()
+	change(o)
+	sampling, deduplicator := sa.configure(o)
+	sa.mutex.Unlock()
+	sampling.flush()
+	deduplicator.flush()
+}
+
+// loggerOptions returns the options the logger is configured with
+func (sa *syncedAppenders) loggerOptions() *options {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+	return sa.options
+}
+
+// setCallDepth sets the number of frames to skip to get to the caller of the logging function
+func (sa *syncedAppenders) setCallDepth(callDepth int) {
+	sa.callDepth = callDepth
+}
+
+// flush hands the summaries of suppressed entries and the collapsed repetitions to the appenders without waiting
+func (sa *syncedAppenders) flush() {
+	sa.sampling.Load().flush()
+	sa.mutex.Lock()
+	deduplicator := sa.deduplicator
+	sa.mutex.Unlock()
+	deduplicator.flush()
 }
 
 // SetLevel sets the current log level of the logger
 func (sa *syncedAppenders) SetLevel(level int) {
-	sa.level = level
+	sa.overrides.set(&sa.level, level)
 }
 
 // Level returns the current log level of the logger
 func (sa *syncedAppenders) Level() int {
-	return sa.level
+	return int(sa.level.Load())
+}
+
+// Enabled returns true if entries at the given level are logged by the logger
+func (sa *syncedAppenders) Enabled(level int) bool {
+	return level <= sa.Level()
+}
+
+// EnabledCtx returns true if entries at the given level are logged by the logger when logged with the context
+func (sa *syncedAppenders) EnabledCtx(ctx context.Context, level int) bool {
+	return enabled(ctx, sa.Level(), level)
+}
+
+// V returns the handle logging entries of the given verbosity level
+func (sa *syncedAppenders) V(level int) Verbose {
+	return Verbose{logger: sa, level: level, enabled: sa.verbose(level, 1)}
+}
+
+// SetVerbosity sets the verbosity of the logger
+func (sa *syncedAppenders) SetVerbosity(verbosity int) {
+	sa.verbosity.level.Store(int32(verbosity))
+}
+
+// Verbosity returns the verbosity of the logger
+func (sa *syncedAppenders) Verbosity() int {
+	return int(sa.verbosity.level.Load())
+}
+
+// verbose returns true if the given verbosity level is enabled for the caller at the given call depth
+func (sa *syncedAppenders) verbose(level int, callDepth int) bool {
+	return sa.verbosity.enabled(level, callDepth+1)
+}
+
+// vmodules returns the current verbosity of the source files matching patterns
+func (sa *syncedAppenders) vmodules() []VModule {
+	return sa.verbosity.vmodules()
+}
+
+// SetVModule sets the verbosity of the source files matching patterns
+func (sa *syncedAppenders) SetVModule(spec string) error {
+	modules, e := ParseVModule(spec)
+	if e != nil {
+		return e
+	}
+	sa.verbosity.setModules(modules)
+	return nil
+}
+
+// PushLevel temporarily sets the level of the logger until the returned function is called
+func (sa *syncedAppenders) PushLevel(level int) func() {
+	return sa.overrides.push(&sa.level, level)
+}
+
+// OverrideLevel temporarily sets the level of the logger for the given duration
+func (sa *syncedAppenders) OverrideLevel(level int, duration time.Duration) func() {
+	return sa.overrides.override(&sa.level, level, duration)
+}
+
+// Writer returns a writer logging each line written to it as an entry at the given level
+func (sa *syncedAppenders) Writer(level int) io.WriteCloser {
+	return NewLineWriter(sa, level, nil)
+}
+
+// SetWriter is not supported by synced appenders loggers
+func (sa *syncedAppenders) SetWriter(io.Writer) error {
+	return ErrUnsupportedByLoggerType
+}
+
+// SetAppenders swaps the appenders the logger hands entries to
+func (sa *syncedAppenders) SetAppenders(appenders ...Appender) error {
+	sa.update(func(o *options) {
+		o.appenders = append([]Appender(nil), appenders...)
+	})
+	return nil
+}
+
+// SetLevelLogPrefix sets the log prefix format for a specific level, used by route writers without an encoder
+func (sa *syncedAppenders) SetLevelLogPrefix(level int, flags ...uint) {
+	sa.update(func(o *options) {
+		o.WithLevelLogPrefix(level, flags...)
+	})
+}
+
+// SetLogPrefix sets the log prefix format for all levels, used by route writers without an encoder
+func (sa *syncedAppenders) SetLogPrefix(flags ...uint) {
+	sa.update(func(o *options) {
+		o.WithLogPrefix(flags...)
+	})
+}
+
+// SetDateFlags sets the format flags of the date in the log prefix, used by route writers without an encoder
+func (sa *syncedAppenders) SetDateFlags(flags int) {
+	sa.update(func(o *options) {
+		o.dateFlags = flags
+	})
+}
+
+// SetFailingCriticals sets if logging a critical should result in a fatal entry (panic)
+func (sa *syncedAppenders) SetFailingCriticals(failing bool) {
+	sa.update(func(o *options) {
+		o.failingCriticals = failing
+	})
 }
 
 // Critical logs the message(s) at the critical level
 func (sa *syncedAppenders) Critical(v ...interface{}) {
-	sa.println(CRITICAL, v...)
+	sa.println(nil, CRITICAL, v...)
 }
 
 // Criticalf logs the formatted message at the critical level
 func (sa *syncedAppenders) Criticalf(format string, v ...interface{}) {
-	sa.printf(CRITICAL, format, v...)
+	sa.printf(nil, CRITICAL, format, v...)
 }
 
 // Error logs the message(s) at the error level
 func (sa *syncedAppenders) Error(v ...interface{}) {
-	sa.println(ERROR, v...)
+	sa.println(nil, ERROR, v...)
 }
 
 // Errorf logs the formatted message at the error level
 func (sa *syncedAppenders) Errorf(format string, v ...interface{}) {
-	sa.printf(ERROR, format, v...)
+	sa.printf(nil, ERROR, format, v...)
 }
 
 // Warning logs the message(s) at the warning level
 func (sa *syncedAppenders) Warning(v ...interface{}) {
-	sa.println(WARNING, v...)
+	sa.println(nil, WARNING, v...)
 }
 
 // Warningf logs the formatted message at the warning level
 func (sa *syncedAppenders) Warningf(format string, v ...interface{}) {
-	sa.printf(WARNING, format, v...)
+	sa.printf(nil, WARNING, format, v...)
 }
 
 // Info logs the message(s) at the info level
 func (sa *syncedAppenders) Info(v ...interface{}) {
-	sa.println(INFO, v...)
+	sa.println(nil, INFO, v...)
 }
 
 // Infof logs the formatted message at the info level
 func (sa *syncedAppenders) Infof(format string, v ...interface{}) {
-	sa.printf(INFO, format, v...)
+	sa.printf(nil, INFO, format, v...)
 }
 
 // Debug logs the message(s) at the debug level
 func (sa *syncedAppenders) Debug(v ...interface{}) {
-	sa.println(DEBUG, v...)
+	sa.println(nil, DEBUG, v...)
 }
 
 // Debugf logs the formatted message at the debug level
 func (sa *syncedAppenders) Debugf(format string, v ...interface{}) {
-	sa.printf(DEBUG, format, v...)
+	sa.printf(nil, DEBUG, format, v...)
 }
 
 // Trace logs the message(s) at the trace level
 func (sa *syncedAppenders) Trace(v ...interface{}) {
-	sa.println(TRACE, v...)
+	sa.println(nil, TRACE, v...)
 }
 
 // Tracef logs the formatted message at the trace level
 func (sa *syncedAppenders) Tracef(format string, v ...interface{}) {
-	sa.printf(TRACE, format, v...)
+	sa.printf(nil, TRACE, format, v...)
+}
+
+// CriticalCtx logs the message(s) at the critical level with the fields extracted from the context
+func (sa *syncedAppenders) CriticalCtx(ctx context.Context, v ...interface{}) {
+	sa.println(ctx, CRITICAL, v...)
+}
+
+// CriticalfCtx logs the formatted message at the critical level with the fields extracted from the context
+func (sa *syncedAppenders) CriticalfCtx(ctx context.Context, format string, v ...interface{}) {
+	sa.printf(ctx, CRITICAL, format, v...)
+}
+
+// ErrorCtx logs the message(s) at the error level with the fields extracted from the context
+func (sa *syncedAppenders) ErrorCtx(ctx context.Context, v ...interface{}) {
+	sa.println(ctx, ERROR, v...)
+}
+
+// ErrorfCtx logs the formatted message at the error level with the fields extracted from the context
+func (sa *syncedAppenders) ErrorfCtx(ctx context.Context, format string, v ...interface{}) {
+	sa.printf(ctx, ERROR, format, v...)
+}
+
+// WarningCtx logs the message(s) at the warning level with the fields extracted from the context
+func (sa *syncedAppenders) WarningCtx(ctx context.Context, v ...interface{}) {
+	sa.println(ctx, WARNING, v...)
+}
+
+// WarningfCtx logs the formatted message at the warning level with the fields extracted from the context
+func (sa *syncedAppenders) WarningfCtx(ctx context.Context, format string, v ...interface{}) {
+	sa.printf(ctx, WARNING, format, v...)
+}
+
+// InfoCtx logs the message(s) at the info level with the fields extracted from the context
+func (sa *syncedAppenders) InfoCtx(ctx context.Context, v ...interface{}) {
+	sa.println(ctx, INFO, v...)
+}
+
+// InfofCtx logs the formatted message at the info level with the fields extracted from the context
+func (sa *syncedAppenders) InfofCtx(ctx context.Context, format string, v ...interface{}) {
+	sa.printf(ctx, INFO, format, v...)
+}
+
+// DebugCtx logs the message(s) at the debug level with the fields extracted from the context
+func (sa *syncedAppenders) DebugCtx(ctx context.Context, v ...interface{}) {
+	sa.println(ctx, DEBUG, v...)
+}
+
+// DebugfCtx logs the formatted message at the debug level with the fields extracted from the context
+func (sa *syncedAppenders) DebugfCtx(ctx context.Context, format string, v ...interface{}) {
+	sa.printf(ctx, DEBUG, format, v...)
+}
+
+// TraceCtx logs the message(s) at the trace level with the fields extracted from the context
+func (sa *syncedAppenders) TraceCtx(ctx context.Context, v ...interface{}) {
+	sa.println(ctx, TRACE, v...)
+}
+
+// TracefCtx logs the formatted message at the trace level with the fields extracted from the context
+func (sa *syncedAppenders) TracefCtx(ctx context.Context, format string, v ...interface{}) {
+	sa.printf(ctx, TRACE, format, v...)
 }
 
 // println logs the message(s) at the provided level
-func (sa *syncedAppenders) println(level int, v ...interface{}) {
-	if level <= sa.level {
-		sa.output(level, fmt.Sprintln(v...))
+func (sa *syncedAppenders) println(ctx context.Context, level int, v ...interface{}) {
+	if sa.EnabledCtx(ctx, level) && sa.sampling.Load().sample(level) {
+		sa.output(ctx, level, fmt.Sprintln(messageValues(v)...), v)
 	}
-	if level == CRITICAL && sa.criticalFailure {
+	if level == CRITICAL && sa.criticalFailure.Load() {
 		panic("critical failure")
 	}
 }
 
 // printf logs the formatted message at the provided level
-func (sa *syncedAppenders) printf(level int, format string, v ...interface{}) {
-	if level <= sa.level {
-		sa.output(level, fmt.Sprintf(format, v...))
+func (sa *syncedAppenders) printf(ctx context.Context, level int, format string, v ...interface{}) {
+	if sa.EnabledCtx(ctx, level) && sa.sampling.Load().sample(level) {
+		sa.output(ctx, level, fmt.Sprintf(format, messageValues(v)...), v)
 	}
-	if level == CRITICAL && sa.criticalFailure {
+	if level == CRITICAL && sa.criticalFailure.Load() {
 		panic("critical failure")
 	}
 }
 
-func (sa *syncedAppenders) output(level int, message string) {
+// output builds the log entry for the message and hands it to all appenders
+func (sa *syncedAppenders) output(ctx context.Context, level int, message string, v []interface{}) {
 	sa.mutex.Lock()
-	defer sa.mutex.Unlock()
+	o, deduplicator := sa.options, sa.deduplicator
+	sa.mutex.Unlock()
 
 	entry := &LogEntry{
+		Logger:    sa.name,
 		Timestamp: time.Now(),
 		Level:     level,
 		Message:   message,
 	}
-	if sa.levelHasSource[level] {
+	entry.recordContext(ctx)
+	entry.recordValues(v)
+	o.redactor.Transform(entry)
+	entry.captureCaller(sa.callDepth)
+	if level <= o.stackTraceLevel {
+		entry.captureStack(sa.callDepth, v)
+	}
+	if deduplicator.filter(entry) {
+		sa.print(entry)
+	}
+}
+
+// log outputs an entry built outside the logger, with its caller already resolved
+func (sa *syncedAppenders) log(entry *LogEntry, enabled bool) {
+	if (enabled || sa.Enabled(entry.Level)) && sa.sampling.Load().sample(entry.Level) {
+		sa.mutex.Lock()
+		o, deduplicator := sa.options, sa.deduplicator
 		sa.mutex.Unlock()
-		_, file, line, ok := runtime.Caller(3)
-		if !ok {
-			file = "???"
-			line = 0
+
+		entry.Logger = sa.name
+		o.redactor.Transform(entry)
+		if deduplicator.filter(entry) {
+			sa.print(entry)
 		}
-		entry.Source = &file
-		entry.Line = line
-		sa.mutex.Lock()
 	}
+	if entry.Level == CRITICAL && sa.criticalFailure.Load() {
+		panic("critical failure")
+	}
+}
+
+// summarize hands the summary of entries suppressed by sampling for a level to all appenders
+func (sa *syncedAppenders) summarize(level int, count int) {
+	sa.print(newSummaryEntry(sa.name, level, count))
+}
+
+// format formats the entry for route writers without an encoder
+func (sa *syncedAppenders) format(buf *[]byte, entry *LogEntry) {
+	var format []uint
+	if entry.Level >= 0 && entry.Level < len(sa.options.levelFormats) {
+		format = sa.options.levelFormats[entry.Level]
+	}
+	formatEntry(buf, entry, format, sa.options.dateFlags, sa.options.stackTraceFormat)
+}
 
+// print runs the entry through the logger's pipeline and hands it to all appenders, or to the matching routes if
+// the logger has routes
+func (sa *syncedAppenders) print(entry *LogEntry) {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+
+	if entry = sa.options.pipeline.Process(entry); entry == nil {
+		return
+	}
+
+	if len(sa.options.routes) > 0 && route(sa.options.routes, entry, &sa.buffer, sa.format) {
+		return
+	}
 	for _, appender := range sa.appenders {
 		appender.Print(entry)
 	}
@@ -0,0 +1,294 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Syslog facilities
+const (
+	FacilityKern = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityLocal0 = iota + 4
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Syslog message formats
+const (
+	RFC5424 = iota // structured syslog protocol (default)
+	RFC3164        // legacy BSD syslog protocol
+)
+
+// DefaultStructuredDataId is the default SD-ID of the structured data element holding the entry fields
+const DefaultStructuredDataId = "fields@32473"
+
+// Maximum lengths of the RFC 5424 header fields
+const (
+	maxHostnameLength = 255
+	maxAppNameLength  = 48
+	maxProcIdLength   = 128
+	maxMsgIdLength    = 32
+)
+
+// syslogSeverities dictionary to translate log levels into syslog severities
+var syslogSeverities = []int{
+	CRITICAL: 2, // crit
+	ERROR:    3, // err
+	WARNING:  4, // warning
+	INFO:     6, // info
+	DEBUG:    7, // debug
+	TRACE:    7, // debug
+}
+
+// SyslogConfig holds the configuration of a syslog appender
+type SyslogConfig struct {
+	Network          string        // network of the syslog server: udp, tcp, unix (stream) or unixgram
+	Address          string        // address of the syslog server (host:port or socket path)
+	Format           int           // message format, RFC5424 or RFC3164
+	Facility         int           // syslog facility of the messages
+	Hostname         string        // hostname reported in the messages (defaults to the os hostname)
+	AppName          string        // app-name (or tag in RFC3164) of the messages (defaults to the process name)
+	ProcId           string        // procid of the messages (defaults to the process id)
+	MsgId            string        // msgid of the messages (defaults to the name of the logger)
+	StructuredDataId string        // SD-ID of the structured data element holding the entry fields (RFC5424 only)
+	DialTimeout      time.Duration // timeout to establish a connection (defaults to DefaultDialTimeout)
+	WriteTimeout     time.Duration // timeout to send an entry, which is dropped if exceeded (defaults to DefaultWriteTimeout)
+}
+
+// SyslogAppender appender sending entries to a syslog server. Stream connections use octet-counted framing
+// (RFC 6587), datagram connections send a message per datagram.
+type SyslogAppender struct {
+	config     SyslogConfig
+	connection net.Conn
+	stream     bool
+	buffer     []byte
+	mutex      sync.Mutex
+}
+
+// NewSyslogAppender creates a syslog appender connected to the configured syslog server
+func NewSyslogAppender(config SyslogConfig) (*SyslogAppender, error) {
+	if len(config.Hostname) == 0 {
+		config.Hostname, _ = os.Hostname()
+	}
+	if len(config.AppName) == 0 {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+	if len(config.ProcId) == 0 {
+		config.ProcId = strconv.Itoa(os.Getpid())
+	}
+	if len(config.StructuredDataId) == 0 {
+		config.StructuredDataId = DefaultStructuredDataId
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = DefaultDialTimeout
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = DefaultWriteTimeout
+	}
+	appender := &SyslogAppender{
+		config: config,
+		stream: !strings.HasPrefix(config.Network, "udp") && config.Network != "unixgram",
+	}
+	if e := appender.connect(); e != nil {
+		return nil, e
+	}
+	return appender, nil
+}
+
+// connect opens the connection to the syslog server
+func (sa *SyslogAppender) connect() error {
+	dialer := &net.Dialer{Timeout: sa.config.DialTimeout}
+	connection, e := dialer.Dial(sa.config.Network, sa.config.Address)
+	if e != nil {
+		return e
+	}
+	sa.connection = connection
+	return nil
+}
+
+// Print sends the entry to the syslog server, reconnecting once if the connection was lost. Entries failing to be
+// sent within the write timeout are dropped.
+func (sa *SyslogAppender) Print(entry *LogEntry) {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+
+	sa.buffer = sa.buffer[:0]
+	if sa.config.Format == RFC3164 {
+		sa.formatRFC3164(entry)
+	} else {
+		sa.formatRFC5424(entry)
+	}
+	message := sa.buffer
+	if sa.stream {
+		message = strconv.AppendInt(make([]byte, 0, len(sa.buffer)+8), int64(len(sa.buffer)), 10)
+		message = append(message, ' ')
+		message = append(message, sa.buffer...)
+	}
+
+	if sa.connection != nil {
+		if sa.write(message) == nil {
+			return
+		}
+		_ = sa.connection.Close()
+		sa.connection = nil
+	}
+	if sa.connect() == nil {
+		_ = sa.write(message)
+	}
+}
+
+// write sends a message through the connection, failing if it's not sent within the write timeout
+func (sa *SyslogAppender) write(message []byte) error {
+	_ = sa.connection.SetWriteDeadline(time.Now().Add(sa.config.WriteTimeout))
+	_, e := sa.connection.Write(message)
+	return e
+}
+
+// Close closes the connection to the syslog server
+func (sa *SyslogAppender) Close() error {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+	if sa.connection == nil {
+		return nil
+	}
+	e := sa.connection.Close()
+	sa.connection = nil
+	return e
+}
+
+// syslogSeverity translates a log level into a syslog severity. Levels beyond TRACE are reported as debug.
+func syslogSeverity(level int) int {
+	if level >= 0 && level < len(syslogSeverities) {
+		return syslogSeverities[level]
+	}
+	return 7
+}
+
+// priority calculates the syslog priority of an entry
+func (sa *SyslogAppender) priority(level int) int {
+	return sa.config.Facility*8 + syslogSeverity(level)
+}
+
+// appendHeaderValue appends a header value, or the nil value if empty. The value is truncated to the maximum length
+// and characters outside of PRINTUSASCII are replaced by '_'.
+func appendHeaderValue(buf []byte, value string, maxLength int) []byte {
+	if len(value) == 0 {
+		return append(buf, '-', ' ')
+	}
+	length := 0
+	for _, c := range value {
+		if length++; length > maxLength {
+			break
+		}
+		if c <= ' ' || c >= 127 {
+			buf = append(buf, '_')
+		} else {
+			buf = append(buf, byte(c))
+		}
+	}
+	return append(buf, ' ')
+}
+
+// formatRFC5424 formats the entry as an RFC 5424 message into the appender's buffer
+func (sa *SyslogAppender) formatRFC5424(entry *LogEntry) {
+	sa.buffer = append(sa.buffer, '<')
+	sa.buffer = strconv.AppendInt(sa.buffer, int64(sa.priority(entry.Level)), 10)
+	sa.buffer = append(sa.buffer, ">1 "...)
+	sa.buffer = entry.Timestamp.AppendFormat(sa.buffer, "2006-01-02T15:04:05.000000Z07:00")
+	sa.buffer = append(sa.buffer, ' ')
+	sa.buffer = appendHeaderValue(sa.buffer, sa.config.Hostname, maxHostnameLength)
+	sa.buffer = appendHeaderValue(sa.buffer, sa.config.AppName, maxAppNameLength)
+	sa.buffer = appendHeaderValue(sa.buffer, sa.config.ProcId, maxProcIdLength)
+	msgId := sa.config.MsgId
+	if len(msgId) == 0 {
+		msgId = entry.Logger
+	}
+	sa.buffer = appendHeaderValue(sa.buffer, msgId, maxMsgIdLength)
+	if len(entry.Fields) == 0 {
+		sa.buffer = append(sa.buffer, '-')
+	} else {
+		sa.buffer = append(sa.buffer, '[')
+		sa.buffer = append(sa.buffer, sa.config.StructuredDataId...)
+		for _, field := range entry.Fields {
+			sa.buffer = append(sa.buffer, ' ')
+			sa.buffer = appendParamName(sa.buffer, field.Key)
+			sa.buffer = append(sa.buffer, '=', '"')
+			sa.buffer = appendParamValue(sa.buffer, fmt.Sprint(field.Value))
+			sa.buffer = append(sa.buffer, '"')
+		}
+		sa.buffer = append(sa.buffer, ']')
+	}
+	if message := strings.TrimSuffix(entry.Message, "\n"); len(message) > 0 {
+		sa.buffer = append(sa.buffer, ' ')
+		sa.buffer = append(sa.buffer, message...)
+	}
+}
+
+// formatRFC3164 formats the entry as an RFC 3164 message into the appender's buffer
+func (sa *SyslogAppender) formatRFC3164(entry *LogEntry) {
+	sa.buffer = append(sa.buffer, '<')
+	sa.buffer = strconv.AppendInt(sa.buffer, int64(sa.priority(entry.Level)), 10)
+	sa.buffer = append(sa.buffer, '>')
+	sa.buffer = entry.Timestamp.AppendFormat(sa.buffer, time.Stamp)
+	sa.buffer = append(sa.buffer, ' ')
+	sa.buffer = appendHeaderValue(sa.buffer, sa.config.Hostname, maxHostnameLength)
+	sa.buffer = append(sa.buffer, sa.config.AppName...)
+	sa.buffer = append(sa.buffer, '[')
+	sa.buffer = append(sa.buffer, sa.config.ProcId...)
+	sa.buffer = append(sa.buffer, "]: "...)
+	sa.buffer = append(sa.buffer, strings.TrimSuffix(entry.Message, "\n")...)
+	for _, field := range entry.Fields {
+		fieldtobuf(&sa.buffer, field)
+	}
+}
+
+// appendParamName appends a structured data parameter name, replacing invalid characters by '_' and truncating it to
+// 32 characters
+func appendParamName(buf []byte, name string) []byte {
+	for i := 0; i < len(name) && i < 32; i++ {
+		if c := name[i]; c <= ' ' || c >= 127 || c == '=' || c == ']' || c == '"' {
+			buf = append(buf, '_')
+		} else {
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+// appendParamValue appends a structured data parameter value, escaping '"', '\' and ']'
+func appendParamValue(buf []byte, value string) []byte {
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c == '"' || c == '\\' || c == ']' {
+			buf = append(buf, '\\', c)
+		} else {
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+var syslogTestEntry = &LogEntry{
+	Logger:    "SYSLOG",
+	Timestamp: time.Date(2021, 6, 25, 10, 30, 15, 123456000, time.UTC),
+	Level:     ERROR,
+	Message:   "connection failed\n",
+	Fields:    []Field{F("host", "db-1"), F("note", `say "hi"]`)},
+}
+
+func TestSyslogAppender(t *testing.T) {
+	t.Run("Test RFC5424 over UDP", func(t *testing.T) {
+		listener, e := net.ListenPacket("udp", "127.0.0.1:0")
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+
+		appender, e := NewSyslogAppender(SyslogConfig{
+			Network:  "udp",
+			Address:  listener.LocalAddr().String(),
+			Facility: FacilityLocal0,
+			Hostname: "host",
+			AppName:  "app",
+			ProcId:   "42",
+		})
+		if e != nil {
+			t.Fatal("Unable to create appender :", e)
+		}
+		defer appender.Close()
+		appender.Print(syslogTestEntry)
+
+		buffer := make([]byte, 1024)
+		_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, e := listener.ReadFrom(buffer)
+		if e != nil {
+			t.Fatal("Failed to receive message :", e)
+		}
+		expected := `<131>1 2021-06-25T10:30:15.123456Z host app 42 SYSLOG [fields@32473 host="db-1" note="say \"hi\"\]"] connection failed`
+		if message := string(buffer[:n]); message != expected {
+			t.Errorf("Unexpected message:\n%s\nexpected:\n%s", message, expected)
+		}
+	})
+
+	t.Run("Test RFC3164 over TCP", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+
+		appender, e := NewSyslogAppender(SyslogConfig{
+			Network:  "tcp",
+			Address:  listener.Addr().String(),
+			Format:   RFC3164,
+			Facility: FacilityDaemon,
+			Hostname: "host",
+			AppName:  "app",
+			ProcId:   "42",
+		})
+		if e != nil {
+			t.Fatal("Unable to create appender :", e)
+		}
+		defer appender.Close()
+		connection, e := listener.Accept()
+		if e != nil {
+			t.Fatal("Failed to accept connection :", e)
+		}
+		defer connection.Close()
+		appender.Print(&LogEntry{Timestamp: syslogTestEntry.Timestamp, Level: WARNING, Message: "disk almost full"})
+
+		_ = connection.SetReadDeadline(time.Now().Add(time.Second))
+		expected := "50 <28>Jun 25 10:30:15 host app[42]: disk almost full"
+		message := make([]byte, len(expected))
+		if _, e := io.ReadFull(connection, message); e != nil || string(message) != expected {
+			t.Errorf("Unexpected message: %q (error %v)", message, e)
+		}
+	})
+	t.Run("Test RFC5424 header limits", func(t *testing.T) {
+		listener, e := net.ListenPacket("udp", "127.0.0.1:0")
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+
+		appender, e := NewSyslogAppender(SyslogConfig{
+			Network:  "udp",
+			Address:  listener.LocalAddr().String(),
+			Hostname: "host",
+			AppName:  strings.Repeat("a", 50),
+			ProcId:   "42",
+			MsgId:    "message idé " + strings.Repeat("m", 30),
+		})
+		if e != nil {
+			t.Fatal("Unable to create appender :", e)
+		}
+		defer appender.Close()
+		appender.Print(&LogEntry{Timestamp: syslogTestEntry.Timestamp, Level: WARNING, Message: "limited"})
+
+		buffer := make([]byte, 1024)
+		_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, e := listener.ReadFrom(buffer)
+		if e != nil {
+			t.Fatal("Failed to receive message :", e)
+		}
+		expected := "<4>1 2021-06-25T10:30:15.123456Z host " + strings.Repeat("a", 48) + " 42 message_id__" + strings.Repeat("m", 20) + " - limited"
+		if message := string(buffer[:n]); message != expected {
+			t.Errorf("Unexpected message:\n%s\nexpected:\n%s", message, expected)
+		}
+	})
+
+	t.Run("Test stalled syslog server", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		if e != nil {
+			t.Fatal("Unable to listen :", e)
+		}
+		defer listener.Close()
+		go func() {
+			for {
+				connection, e := listener.Accept()
+				if e != nil {
+					return
+				}
+				defer connection.Close()
+			}
+		}()
+
+		appender, e := NewSyslogAppender(SyslogConfig{
+			Network:      "tcp",
+			Address:      listener.Addr().String(),
+			WriteTimeout: 20 * time.Millisecond,
+		})
+		if e != nil {
+			t.Fatal("Unable to create appender :", e)
+		}
+		defer appender.Close()
+
+		printed := make(chan struct{})
+		go func() {
+			message := strings.Repeat("x", 1<<20)
+			for i := 0; i < 20; i++ {
+				appender.Print(&LogEntry{Level: INFO, Message: message})
+			}
+			close(printed)
+		}()
+		select {
+		case <-printed:
+		case <-time.After(5 * time.Second):
+			t.Error("Printing blocked by a syslog server not reading messages")
+		}
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// VerbosityKey is the key of the field holding the verbosity of entries logged through a Verbose handle
+const VerbosityKey = "v"
+
+// VModule verbosity of the source files matching a pattern. Patterns without a '/' are matched against the source
+// file name without the .go extension (like "db*"), other patterns against the module relative source path without
+// the extension (like "controllers/*"), using the syntax of path.Match.
+type VModule struct {
+	Pattern   string
+	Verbosity int
+}
+
+// ParseVModule parses a comma separated list of pattern=verbosity pairs (like "db*=2,controllers/*=4")
+func ParseVModule(spec string) ([]VModule, error) {
+	var modules []VModule
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); len(pair) == 0 {
+			continue
+		}
+		separator := strings.LastIndexByte(pair, '=')
+		if separator <= 0 {
+			return nil, ErrInvalidVModule.WithValues(pair)
+		}
+		pattern := pair[:separator]
+		verbosity, e := strconv.Atoi(pair[separator+1:])
+		if _, matchError := path.Match(pattern, ""); e != nil || matchError != nil || verbosity < 0 {
+			return nil, ErrInvalidVModule.WithValues(pair)
+		}
+		modules = append(modules, VModule{Pattern: pattern, Verbosity: verbosity})
+	}
+	return modules, nil
+}
+
+// verbosity keeps track of the verbosity of a logger and the verbosity of its vmodules, caching the verbosity of the
+// callers matching a vmodule
+type verbosity struct {
+	level   atomic.Int32
+	modules atomic.Pointer[[]VModule]
+	callers atomic.Pointer[sync.Map] // verbosity of the callers by program counter, reset when the vmodules change
+}
+
+// set sets the verbosity and vmodules
+func (v *verbosity) set(level int, modules []VModule) {
+	v.level.Store(int32(level))
+	v.setModules(modules)
+}
+
+// setModules sets the vmodules, resetting the cached verbosity of the callers
+func (v *verbosity) setModules(modules []VModule) {
+	modules = append([]VModule(nil), modules...)
+	v.callers.Store(&sync.Map{})
+	v.modules.Store(&modules)
+}
+
+// vmodules returns the current vmodules
+func (v *verbosity) vmodules() []VModule {
+	if modules := v.modules.Load(); modules != nil {
+		return append([]VModule(nil), *modules...)
+	}
+	return nil
+}
+
+// enabled returns true if the verbosity, or the verbosity of the vmodule matching the source of the caller at the
+// given call depth, is at least the given level
+func (v *verbosity) enabled(level int, callDepth int) bool {
+	if level <= int(v.level.Load()) {
+		return true
+	}
+	modules := v.modules.Load()
+	if modules == nil || len(*modules) == 0 {
+		return false
+	}
+	pc, file, _, ok := runtime.Caller(callDepth + 1)
+	if !ok {
+		return false
+	}
+	callers := v.callers.Load()
+	if cached, found := callers.Load(pc); found {
+		return level <= cached.(int)
+	}
+	moduleSource := strings.TrimSuffix(resolveCaller(pc, file).moduleSource, ".go")
+	name := strings.TrimSuffix(path.Base(file), ".go")
+	callerVerbosity := 0
+	for _, module := range *modules {
+		target := name
+		if strings.ContainsRune(module.Pattern, '/') {
+			target = moduleSource
+		}
+		if matched, _ := path.Match(module.Pattern, target); matched {
+			callerVerbosity = module.Verbosity
+			break
+		}
+	}
+	callers.Store(pc, callerVerbosity)
+	return level <= callerVerbosity
+}
+
+// Verbose handle logging entries of a verbosity level, returned by a logger's V. Entries are logged at the DEBUG
+// level, with the verbosity level in their VerbosityKey field, if the logger's verbosity allows it, whatever the
+// level of the logger.
+type Verbose struct {
+	logger  Logger
+	level   int
+	enabled bool
+}
+
+// Enabled returns true if entries logged through the handle are logged
+func (v Verbose) Enabled() bool {
+	return v.enabled
+}
+
+// Info logs the message(s) if the verbosity level is enabled
+func (v Verbose) Info(args ...interface{}) {
+	if v.enabled {
+		v.log(fmt.Sprintln(messageValues(args)...), args)
+	}
+}
+
+// Infof logs the formatted message if the verbosity level is enabled
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v.enabled {
+		v.log(fmt.Sprintf(format, messageValues(args)...), args)
+	}
+}
+
+// log logs the message with the source of the caller of Info or Infof
+func (v Verbose) log(message string, args []interface{}) {
+	entry := &LogEntry{
+		Timestamp: time.Now(),
+		Level:     DEBUG,
+		Message:   message,
+	}
+	entry.recordValues(args)
+	entry.Fields = append(entry.Fields, F(VerbosityKey, v.level))
+	entry.captureCaller(2)
+	logEnabledEntry(v.logger, entry)
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"testing"
+)
+
+func TestVerbosity(t *testing.T) {
+	t.Run("Test logger verbosity", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("VERBOSE", SyncedAppenders().WithAppenders(appender).WithVerbosity(2).(*options))
+		logger.V(2).Infof("shown %d", 2)
+		logger.V(3).Info("hidden")
+		if logger.V(3).Enabled() || !logger.V(1).Enabled() {
+			t.Error("Unexpected enabled verbosity levels.")
+		}
+		if len(appender.entries) != 1 {
+			t.Fatal("Unexpected entries :", appender.entries)
+		}
+		entry := appender.entries[0]
+		if entry.Level != DEBUG || entry.Message != "shown 2" || len(entry.Fields) != 1 || entry.Fields[0] != F(VerbosityKey, 2) {
+			t.Error("Unexpected verbose entry :", entry)
+		}
+		if entry.Function != "TestVerbosity.func1" {
+			t.Error("Caller of the verbose handle not resolved :", entry.Function)
+		}
+	})
+
+	t.Run("Test vmodules", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("VERBOSE", SyncedAppenders().WithAppenders(appender).WithVModule("other=5,verbosity_*=3").(*options))
+		logger.V(3).Info("matched")
+		logger.V(4).Info("hidden")
+		if e := logger.SetVModule("*/verbosity_test=1"); e != nil {
+			t.Error("Setting a valid vmodule failed :", e)
+		}
+		logger.V(2).Info("hidden")
+		if e := logger.SetVModule("verbosity_test=1,[=2"); !ErrInvalidVModule.IsKindOf(e) {
+			t.Error("Setting an invalid vmodule should fail :", e)
+		}
+		if len(appender.entries) != 1 || appender.entries[0].Message != "matched\n" {
+			t.Error("Unexpected entries :", appender.entries)
+		}
+	})
+
+	t.Run("Test vmodules from options", func(t *testing.T) {
+		appender := &recordingAppender{}
+		modules := []VModule{{Pattern: "verbosity_*", Verbosity: 3}}
+		logger, _ := newLogger("VERBOSE", SyncedAppenders().WithAppenders(appender).WithVModules(modules...).(*options))
+		modules[0].Verbosity = 5
+		logger.V(4).Info("hidden")
+		logger.V(3).Info("matched")
+		if len(appender.entries) != 1 || appender.entries[0].Message != "matched\n" {
+			t.Error("Unexpected entries :", appender.entries)
+		}
+	})
+
+	t.Run("Test parsing vmodules", func(t *testing.T) {
+		modules, e := ParseVModule(" db*=2, controllers/*=4 ,")
+		if e != nil || len(modules) != 2 || modules[0] != (VModule{"db*", 2}) || modules[1] != (VModule{"controllers/*", 4}) {
+			t.Error("Unexpected vmodules :", modules, e)
+		}
+		for _, spec := range []string{"db", "=2", "db=x", "db=-1"} {
+			if _, e := ParseVModule(spec); !ErrInvalidVModule.IsKindOf(e) {
+				t.Errorf("Parsing %q should fail : %v", spec, e)
+			}
+		}
+	})
+
+	t.Run("Test registry verbosity", func(t *testing.T) {
+		registry := NewRegistry()
+		_, _ = registry.Get("VERBOSE")
+		if e := registry.SetLoggerVerbosity("VERBOSE", 4); e != nil {
+			t.Error("Setting the verbosity of an existing logger failed :", e)
+		}
+		if e := registry.SetLoggerVModule("VERBOSE", "db*=2"); e != nil {
+			t.Error("Setting the vmodule of an existing logger failed :", e)
+		}
+		if verbosities := registry.LoggerVerbosities(); len(verbosities) != 2 || verbosities["VERBOSE"] != 4 || verbosities[DEFAULT] != 0 {
+			t.Error("Unexpected verbosities :", verbosities)
+		}
+		if d := registry.Loggers()[1]; d.Verbosity != 4 || len(d.VModules) != 1 {
+			t.Error("Descriptor not reporting the verbosity :", d)
+		}
+		if e := registry.SetLoggerVerbosity("Missing", 1); e != ErrLoggerDoesNotExist {
+			t.Error("Setting the verbosity of a missing logger should fail :", e)
+		}
+	})
+
+	t.Run("Test default logger verbosity", func(t *testing.T) {
+		resetLoggers()
+		_ = SetLoggerVModule(DEFAULT, "verbosity_test=1")
+		V(1).Info("default")
+		if buf.String() != "default v=1\n" {
+			t.Errorf("Unexpected default logger output: %q", buf.String())
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxLineLength is the length after which a partial line written to a line writer is logged without waiting for its
+// end
+const MaxLineLength = 64 * 1024
+
+// LevelDetector detects the level of a line written to a line writer, returning the level and the message without the
+// level marker, or false if the line holds no level marker
+type LevelDetector func(line string) (level int, message string, found bool)
+
+// level markers recognized by DetectLevel
+var levelMarkers = map[string]int{
+	"CRITICAL": CRITICAL, "CRT": CRITICAL, "FATAL": CRITICAL,
+	"ERROR": ERROR, "ERR": ERROR,
+	"WARNING": WARNING, "WRN": WARNING, "WARN": WARNING,
+	"INFO": INFO, "INF": INFO,
+	"DEBUG": DEBUG, "DBG": DEBUG,
+	"TRACE": TRACE, "TRC": TRACE,
+}
+
+// DetectLevel detects level markers at the start of a line, either followed by a colon (like "ERROR:" or "warn:") or
+// between brackets (like "[ERR]" or "[DEBUG]"), using the level names and the level tokens of the log headers
+func DetectLevel(line string) (int, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	var marker, rest string
+	if strings.HasPrefix(trimmed, "[") {
+		end := strings.IndexByte(trimmed, ']')
+		if end < 0 {
+			return 0, line, false
+		}
+		marker, rest = trimmed[1:end], trimmed[end+1:]
+	} else {
+		end := strings.IndexByte(trimmed, ':')
+		if end < 0 {
+			return 0, line, false
+		}
+		marker, rest = trimmed[:end], trimmed[end+1:]
+	}
+	if level, found := levelMarkers[strings.ToUpper(marker)]; found {
+		return level, strings.TrimLeft(rest, " \t"), true
+	}
+	return 0, line, false
+}
+
+// lineWriter writer logging each line written to it as an entry, buffering partial lines until they're complete
+type lineWriter struct {
+	logger  Logger
+	level   int
+	detect  LevelDetector
+	partial []byte
+	closed  bool
+	mutex   sync.Mutex
+}
+
+// NewLineWriter creates a writer logging each line written to it as an entry of the logger at the given level
+// (like the output of a subprocess). Partial lines are buffered until they're complete, the writer is closed or they
+// exceed MaxLineLength. If a level detector is given, lines with a detected level are logged at that level instead.
+func NewLineWriter(logger Logger, level int, detect LevelDetector) io.WriteCloser {
+	return &lineWriter{logger: logger, level: level, detect: detect}
+}
+
+// Write logs the complete lines written so far
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+
+	if lw.closed {
+		return 0, os.ErrClosed
+	}
+	lw.partial = append(lw.partial, p...)
+	for {
+		end := bytes.IndexByte(lw.partial, '\n')
+		if end < 0 {
+			if len(lw.partial) < MaxLineLength {
+				break
+			}
+			end = MaxLineLength
+			lw.emit(lw.partial[:end])
+			lw.partial = lw.partial[end:]
+			continue
+		}
+		lw.emit(lw.partial[:end])
+		lw.partial = lw.partial[end+1:]
+	}
+	// keep the partial line at the start of the buffer so it doesn't grow indefinitely
+	lw.partial = append(lw.partial[:0:0], lw.partial...)
+	return len(p), nil
+}
+
+// Close logs the pending partial line, if any. Writing after closing fails.
+func (lw *lineWriter) Close() error {
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+
+	if !lw.closed && len(lw.partial) > 0 {
+		lw.emit(lw.partial)
+		lw.partial = nil
+	}
+	lw.closed = true
+	return nil
+}
+
+// emit logs a line, detecting its level if the writer has a level detector. Must be called while holding the lock.
+func (lw *lineWriter) emit(line []byte) {
+	entry := &LogEntry{
+		Timestamp: time.Now(),
+		Level:     lw.level,
+		Message:   strings.TrimSuffix(string(line), "\r"),
+	}
+	if lw.detect != nil {
+		if level, message, found := lw.detect(entry.Message); found {
+			entry.Level, entry.Message = level, message
+		}
+	}
+	entry.captureCallerSkipping(2, "io", "bufio", "fmt", "os/exec")
+	logEntry(lw.logger, entry)
+}
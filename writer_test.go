@@ -0,0 +1,64 @@
+// Copyright 2020 GOM. All rights reserved.
+// Since 25/06/2021 By GOM
+// Licensed under MIT License
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	t.Run("Test writing lines", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("LINES", SyncedAppenders().WithAppenders(appender).WithStartingLevel(INFO).(*options))
+		writer := logger.Writer(INFO)
+		_, _ = writer.Write([]byte("first\r\nsec"))
+		_, _ = fmt.Fprint(writer, "ond\nthird")
+		if len(appender.entries) != 2 || appender.entries[0].Message != "first" || appender.entries[1].Message != "second" {
+			t.Error("Unexpected entries for complete lines :", appender.entries)
+		}
+		if appender.entries[1].Function != "TestLineWriter.func1" {
+			t.Error("Caller of the writer not resolved :", appender.entries[1].Function)
+		}
+		_ = writer.Close()
+		if len(appender.entries) != 3 || appender.entries[2].Message != "third" || appender.entries[2].Level != INFO {
+			t.Error("Partial line not logged when closing :", appender.entries)
+		}
+		if _, e := writer.Write([]byte("closed\n")); e != os.ErrClosed {
+			t.Error("Writing after closing should fail :", e)
+		}
+	})
+
+	t.Run("Test long partial lines", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("LINES", SyncedAppenders().WithAppenders(appender).(*options))
+		writer := logger.Writer(WARNING)
+		_, _ = writer.Write([]byte(strings.Repeat("x", MaxLineLength+1)))
+		if len(appender.entries) != 1 || len(appender.entries[0].Message) != MaxLineLength {
+			t.Error("Long partial line not logged.")
+		}
+	})
+
+	t.Run("Test detecting levels", func(t *testing.T) {
+		appender := &recordingAppender{}
+		logger, _ := newLogger("LINES", SyncedAppenders().WithAppenders(appender).WithStartingLevel(TRACE).(*options))
+		writer := NewLineWriter(logger, INFO, DetectLevel)
+		_, _ = writer.Write([]byte("ERROR: failed\n  [dbg] details\nwarn:careful\nplain: text\n[unknown] text\n"))
+		expected := []struct {
+			level   int
+			message string
+		}{{ERROR, "failed"}, {DEBUG, "details"}, {WARNING, "careful"}, {INFO, "plain: text"}, {INFO, "[unknown] text"}}
+		if len(appender.entries) != len(expected) {
+			t.Fatal("Unexpected entries :", appender.entries)
+		}
+		for i, e := range expected {
+			if entry := appender.entries[i]; entry.Level != e.level || entry.Message != e.message {
+				t.Errorf("Unexpected entry (expected %v %q, got %v %q)", e.level, e.message, entry.Level, entry.Message)
+			}
+		}
+	})
+}